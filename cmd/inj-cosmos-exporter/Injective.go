@@ -12,9 +12,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 /*
@@ -153,10 +151,11 @@ func getInjMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *InjMe
 	}()
 }
 func InjMetricHandler(w http.ResponseWriter, r *http.Request, s *exporter.Service) {
+	s = s.ForEndpoint(r, "/metrics/injective")
 	requestStart := time.Now()
 
 	sublogger := s.Log.With().
-		Str("request-id", uuid.New().String()).
+		Str("request-id", exporter.RequestID(r)).
 		Logger()
 
 	address := r.URL.Query().Get("address")
@@ -168,7 +167,7 @@ func InjMetricHandler(w http.ResponseWriter, r *http.Request, s *exporter.Servic
 			Msg("Could not get address")
 		return
 	}
-	registry := prometheus.NewRegistry()
+	registry := s.Config.NewRegistry()
 	injMetrics := NewInjMetrics(registry, s.Config)
 
 	var wg sync.WaitGroup
@@ -177,8 +176,7 @@ func InjMetricHandler(w http.ResponseWriter, r *http.Request, s *exporter.Servic
 
 	wg.Wait()
 
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	h.ServeHTTP(w, r)
+	s.ServeMetrics(w, r, registry)
 	sublogger.Info().
 		Str("method", "GET").
 		Str("endpoint", "/metrics/injective").
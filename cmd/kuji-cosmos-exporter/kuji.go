@@ -11,9 +11,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 /*
@@ -70,10 +68,11 @@ func getKujiMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *Kuji
 	}()
 }
 func KujiraMetricHandler(w http.ResponseWriter, r *http.Request, s *exporter.Service) {
+	s = s.ForEndpoint(r, "/metrics/kujira")
 	requestStart := time.Now()
 
 	sublogger := s.Log.With().
-		Str("request-id", uuid.New().String()).
+		Str("request-id", exporter.RequestID(r)).
 		Logger()
 
 	address := r.URL.Query().Get("address")
@@ -85,7 +84,7 @@ func KujiraMetricHandler(w http.ResponseWriter, r *http.Request, s *exporter.Ser
 			Msg("Could not get address")
 		return
 	}
-	registry := prometheus.NewRegistry()
+	registry := s.Config.NewRegistry()
 	kujiMetrics := NewKujiMetrics(registry, s.Config)
 
 	var wg sync.WaitGroup
@@ -93,8 +92,7 @@ func KujiraMetricHandler(w http.ResponseWriter, r *http.Request, s *exporter.Ser
 
 	wg.Wait()
 
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	h.ServeHTTP(w, r)
+	s.ServeMetrics(w, r, registry)
 	sublogger.Info().
 		Str("method", "GET").
 		Str("endpoint", "/metrics/kujira").
@@ -5,8 +5,6 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
-	"github.com/spf13/pflag"
-	"github.com/spf13/viper"
 	"main/pkg/exporter"
 	"net/http"
 	"os"
@@ -19,32 +17,11 @@ var rootCmd = &cobra.Command{
 	Use:  "kuji-cosmos-exporter",
 	Long: "Scrape the data about the validators set, specific validators or wallets in the Cosmos network.",
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		if config.ConfigPath == "" {
-			config.SetBechPrefixes(cmd)
-
-			return nil
-		}
-
-		viper.SetConfigFile(config.ConfigPath)
-		if err := viper.ReadInConfig(); err != nil {
-			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-				log.Info().Err(err).Msg("Error reading config file")
-				return err
-			}
+		if err := config.LoadConfig(cmd); err != nil {
+			log.Info().Err(err).Msg("Error reading config")
+			return err
 		}
 
-		// Credits to https://carolynvanslyck.com/blog/2020/08/sting-of-the-viper/
-		cmd.Flags().VisitAll(func(f *pflag.Flag) {
-			if !f.Changed && viper.IsSet(f.Name) {
-				val := viper.Get(f.Name)
-				if err := cmd.Flags().Set(f.Name, fmt.Sprintf("%v", val)); err != nil {
-					log.Fatal().Err(err).Msg("Could not set flag")
-				}
-			}
-		})
-
-		config.SetBechPrefixes(cmd)
-
 		return nil
 	},
 	Run: Execute,
@@ -71,7 +48,7 @@ func Execute(_ *cobra.Command, _ []string) {
 	sdkconfig.SetBech32PrefixForConsensusNode(config.ConsensusNodePrefix, config.ConsensusNodePubkeyPrefix)
 	sdkconfig.Seal()
 
-	s := &exporter.Service{}
+	s := exporter.NewService()
 	s.Log = log
 	// Setup gRPC connection
 	err = s.Connect(&config)
@@ -87,7 +64,14 @@ func Execute(_ *cobra.Command, _ []string) {
 	}(s)
 
 	s.SetChainID(&config)
+	s.CheckRPC(&config)
+	if err := config.CheckConstLabels(); err != nil {
+		log.Fatal().Err(err).Msg("Invalid const labels")
+	}
 	s.SetDenom(&config)
+	if err := config.Validate(); err != nil {
+		log.Fatal().Err(err).Msg("Invalid config")
+	}
 	/*
 		eventCollector, err := NewEventCollector(TendermintRPC, log, BankTransferThreshold)
 		if err != nil {
@@ -112,11 +96,55 @@ func Execute(_ *cobra.Command, _ []string) {
 	http.HandleFunc("/metrics/validator", s.ValidatorHandler)
 	http.HandleFunc("/metrics/validators", s.ValidatorsHandler)
 	http.HandleFunc("/metrics/params", s.ParamsHandler)
+	http.HandleFunc("/metrics/staking", s.StakingHandler)
 	http.HandleFunc("/metrics/general", s.GeneralHandler)
 
 	http.HandleFunc("/metrics/delegator", s.DelegatorHandler)
+	http.HandleFunc("/metrics/delegator-delegations", s.DelegatorDelegationsHandler)
 	http.HandleFunc("/metrics/proposals", s.ProposalsHandler)
 	http.HandleFunc("/metrics/upgrade", s.UpgradeHandler)
+	http.HandleFunc("/metrics/gas", s.GasHandler)
+	http.HandleFunc("/metrics/ibc", s.IBCHandler)
+	http.HandleFunc("/metrics/consensus", s.ConsensusHandler)
+	http.HandleFunc("/metrics/consensus-params", s.ConsensusParamsHandler)
+	http.HandleFunc("/metrics/validator-set", s.ValidatorSetHandler)
+	http.HandleFunc("/metrics/denom-metadata", s.DenomMetadataHandler)
+	if config.FeeMarket != "" {
+		http.HandleFunc("/metrics/feemarket", s.FeeMarketHandler)
+	}
+	if config.OracleModule != "" {
+		http.HandleFunc("/metrics/oracle", s.OracleHandler)
+	}
+	if config.AuthzGranter != "" {
+		http.HandleFunc("/metrics/authz", s.AuthzHandler)
+	}
+	if config.GroupID != 0 {
+		http.HandleFunc("/metrics/group", s.GroupHandler)
+	}
+	if config.Epochs {
+		http.HandleFunc("/metrics/epochs", s.EpochsHandler)
+	}
+	if config.Wasm {
+		http.HandleFunc("/metrics/wasm", s.WasmHandler)
+		http.HandleFunc("/metrics/wasm/contract", s.WasmContractHandler)
+	}
+	if config.RecentBlocks > 0 {
+		http.HandleFunc("/metrics/recent-signatures", s.RecentSignaturesHandler)
+	}
+	if config.TotalDelegators {
+		http.HandleFunc("/metrics/total-delegators", s.TotalDelegatorsHandler)
+	}
+	if len(config.VestingAddresses) > 0 {
+		http.HandleFunc("/metrics/vesting", s.VestingHandler)
+	}
+	if config.RawStorePath != "" {
+		http.HandleFunc("/metrics/raw-store", s.RawStoreHandler)
+	}
+	http.HandleFunc("/ready", s.ReadyHandler)
+
+	if config.PrewarmCache {
+		go s.PrewarmCache()
+	}
 	if config.Prefix == "kujira" {
 		http.HandleFunc("/metrics/kujira", func(w http.ResponseWriter, r *http.Request) { KujiraMetricHandler(w, r, s) })
 	}
@@ -133,8 +161,12 @@ func Execute(_ *cobra.Command, _ []string) {
 			eventCollector.StreamHandler(w, r)
 		})
 	*/
+	listener, err := config.Listen()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not listen")
+	}
 	log.Info().Str("address", config.ListenAddress).Msg("Listening")
-	err = http.ListenAndServe(config.ListenAddress, nil)
+	err = http.Serve(listener, s.RecoverHandler(s.CoalesceHandler(s.CircuitBreakerHandler(http.DefaultServeMux))))
 	if err != nil {
 		log.Fatal().Err(err).Msg("Could not start application")
 	}
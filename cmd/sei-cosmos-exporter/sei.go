@@ -10,9 +10,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	oracletypes "github.com/sei-protocol/sei-chain/x/oracle/types"
 )
 
@@ -77,10 +75,11 @@ func getSeiMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *SeiMe
 
 }
 func OracleMetricHandler(w http.ResponseWriter, r *http.Request, s *exporter.Service, _ *exporter.ServiceConfig) {
+	s = s.ForEndpoint(r, "/metrics/sei")
 	requestStart := time.Now()
 
 	sublogger := s.Log.With().
-		Str("request-id", uuid.New().String()).
+		Str("request-id", exporter.RequestID(r)).
 		Logger()
 
 	address := r.URL.Query().Get("address")
@@ -93,7 +92,7 @@ func OracleMetricHandler(w http.ResponseWriter, r *http.Request, s *exporter.Ser
 		return
 	}
 
-	registry := prometheus.NewRegistry()
+	registry := s.Config.NewRegistry()
 	seiMetrics := NewSeiMetrics(registry, s.Config)
 
 	var wg sync.WaitGroup
@@ -101,8 +100,7 @@ func OracleMetricHandler(w http.ResponseWriter, r *http.Request, s *exporter.Ser
 
 	wg.Wait()
 
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	h.ServeHTTP(w, r)
+	s.ServeMetrics(w, r, registry)
 	sublogger.Info().
 		Str("method", "GET").
 		Str("endpoint", "/metrics/sei").
@@ -2,9 +2,6 @@ package main
 
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/google/uuid"
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"main/pkg/exporter"
 	"net/http"
 	"sync"
@@ -12,13 +9,14 @@ import (
 )
 
 func SeiSingleHandler(w http.ResponseWriter, r *http.Request, s *exporter.Service) {
+	s = s.ForEndpoint(r, "/metrics")
 	requestStart := time.Now()
 
 	sublogger := log.With().
-		Str("request-id", uuid.New().String()).
+		Str("request-id", exporter.RequestID(r)).
 		Logger()
 
-	registry := prometheus.NewRegistry()
+	registry := s.Config.NewRegistry()
 	generalMetrics := exporter.NewGeneralMetrics(registry, s.Config)
 	var validatorMetrics *exporter.ValidatorMetrics
 	var paramsMetrics *exporter.ParamsMetrics
@@ -164,8 +162,7 @@ func SeiSingleHandler(w http.ResponseWriter, r *http.Request, s *exporter.Servic
 	}
 	wg.Wait()
 
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	h.ServeHTTP(w, r)
+	s.ServeMetrics(w, r, registry)
 	sublogger.Info().
 		Str("method", "GET").
 		Str("endpoint", "/metrics").
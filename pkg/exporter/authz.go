@@ -0,0 +1,132 @@
+package exporter
+
+import (
+	"context"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type AuthzMetrics struct {
+	grantExpirationGauge *prometheus.GaugeVec
+}
+
+func NewAuthzMetrics(reg prometheus.Registerer, config *ServiceConfig) *AuthzMetrics {
+	m := &AuthzMetrics{
+		grantExpirationGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_authz_grant_expiration",
+				Help:        "Expiration time of the authz grant given by the granter, as unix timestamp",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"granter", "grantee", "msg_type"},
+		),
+	}
+	reg.MustRegister(m.grantExpirationGauge)
+	return m
+}
+func GetAuthzMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *AuthzMetrics, s *Service, config *ServiceConfig) {
+	// needed to find out the message type of the send and stake authorizations, the generic one carries it itself
+	interfaceRegistry := codectypes.NewInterfaceRegistry()
+	authz.RegisterInterfaces(interfaceRegistry)
+	banktypes.RegisterInterfaces(interfaceRegistry)
+	stakingtypes.RegisterInterfaces(interfaceRegistry)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Str("granter", config.AuthzGranter).Msg("Started querying authz grants")
+		queryStart := time.Now()
+
+		authzClient := authz.NewQueryClient(s.GrpcConn)
+
+		grants, err := Paginate(func(key []byte) ([]*authz.GrantAuthorization, []byte, error) {
+			grantsResponse, err := authzClient.GranterGrants(
+				context.Background(),
+				&authz.QueryGranterGrantsRequest{
+					Granter: config.AuthzGranter,
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return grantsResponse.Grants, grantsResponse.Pagination.GetNextKey(), nil
+		})
+		if err != nil {
+			sublogger.Error().
+				Str("granter", config.AuthzGranter).
+				Err(err).
+				Msg("Could not get authz grants")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int("grantsLength", len(grants)).
+			Msg("Finished querying authz grants")
+
+		for _, grant := range grants {
+			if grant.Expiration == nil {
+				sublogger.Trace().
+					Str("grantee", grant.Grantee).
+					Msg("Grant doesn't expire, not returning expiration.")
+				continue
+			}
+
+			var msgType string
+			var authorization authz.Authorization
+			if err := interfaceRegistry.UnpackAny(grant.Authorization, &authorization); err != nil {
+				sublogger.Debug().
+					Str("grantee", grant.Grantee).
+					Str("type", grant.Authorization.GetTypeUrl()).
+					Err(err).
+					Msg("Could not unpack authorization, using its type instead")
+				msgType = grant.Authorization.GetTypeUrl()
+			} else {
+				msgType = authorization.MsgTypeURL()
+			}
+
+			metrics.grantExpirationGauge.With(prometheus.Labels{
+				"granter":  grant.Granter,
+				"grantee":  grant.Grantee,
+				"msg_type": msgType,
+			}).Set(float64(grant.Expiration.Unix()))
+		}
+	}()
+
+}
+func (s *Service) AuthzHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/authz")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	authzMetrics := NewAuthzMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetAuthzMetrics(&wg, &sublogger, authzMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/authz").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
@@ -0,0 +1,108 @@
+package exporter
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type scrapeQueriesKey struct{}
+
+// scrapeQueries counts the gRPC and RPC queries of a scrape which succeeded and failed, the ones of the background
+// refreshes and of the concurrent scrapes aren't counted with them
+type scrapeQueries struct {
+	successes atomic.Uint64
+	failures  atomic.Uint64
+}
+
+// record records the result of a query, q can be nil outside CircuitBreakerHandler
+func (q *scrapeQueries) record(err error) {
+	if q == nil {
+		return
+	}
+	if err != nil {
+		q.failures.Add(1)
+	} else {
+		q.successes.Add(1)
+	}
+}
+
+// failed returns whether some of the queries failed and none succeeded
+func (q *scrapeQueries) failed() bool {
+	return q.failures.Load() > 0 && q.successes.Load() == 0
+}
+
+// CircuitBreaker counts the consecutive failed scrapes, and opens once there are too many of them,
+// so the scrapes stop querying a node which is down until it had the time to recover
+type CircuitBreaker struct {
+	consecutiveFailures int
+	openUntil           time.Time
+	mutex               sync.Mutex
+}
+
+// Open returns whether the scrapes are short-circuited at now
+func (b *CircuitBreaker) Open(now time.Time) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return now.Before(b.openUntil)
+}
+
+// Record records the result of a scrape, and returns true if it opened the breaker for the cooldown, after threshold
+// consecutive failed scrapes. The first scrape let through after the cooldown opens it again if it fails too.
+func (b *CircuitBreaker) Record(failed bool, threshold int, cooldown time.Duration, now time.Time) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !failed {
+		b.consecutiveFailures = 0
+		return false
+	}
+
+	b.consecutiveFailures++
+	if threshold <= 0 || b.consecutiveFailures < threshold {
+		return false
+	}
+	b.openUntil = now.Add(cooldown)
+	return true
+}
+
+// CircuitBreakerHandler stops the metrics scrapes from querying the node after --circuit-breaker-failures consecutive
+// failed ones, only serving the exporter metrics with cosmos_exporter_circuit_open = 1 until --circuit-breaker-cooldown
+// is over. A scrape failed if some of its gRPC or RPC queries failed and none succeeded.
+func (s *Service) CircuitBreakerHandler(next http.Handler) http.Handler {
+	if s.Config.CircuitBreakerFailures <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/metrics") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if s.circuitBreaker.Open(time.Now()) {
+			s.Log.Debug().
+				Str("request-id", RequestID(r)).
+				Str("endpoint", r.URL.Path).
+				Msg("Circuit is open, not querying the node")
+			s.ServeMetrics(w, r, s.Config.NewRegistry())
+			return
+		}
+
+		queries := &scrapeQueries{}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), scrapeQueriesKey{}, queries)))
+
+		if s.circuitBreaker.Record(queries.failed(), s.Config.CircuitBreakerFailures, s.Config.CircuitBreakerCooldown, time.Now()) {
+			s.Log.Warn().
+				Str("request-id", RequestID(r)).
+				Str("endpoint", r.URL.Path).
+				Int("failures", s.Config.CircuitBreakerFailures).
+				Dur("cooldown", s.Config.CircuitBreakerCooldown).
+				Msg("Opened the circuit after consecutive failed scrapes, not querying the node until the cooldown is over")
+		}
+	})
+}
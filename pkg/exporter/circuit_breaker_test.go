@@ -0,0 +1,77 @@
+package exporter_test
+
+import (
+	"main/pkg/exporter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var breaker exporter.CircuitBreaker
+	now := time.Now()
+
+	require.False(t, breaker.Record(true, 3, time.Minute, now))
+	require.False(t, breaker.Record(true, 3, time.Minute, now))
+	require.False(t, breaker.Open(now))
+
+	// a scrape which succeeded starts the count again
+	require.False(t, breaker.Record(false, 3, time.Minute, now))
+	require.False(t, breaker.Record(true, 3, time.Minute, now))
+	require.False(t, breaker.Record(true, 3, time.Minute, now))
+	require.True(t, breaker.Record(true, 3, time.Minute, now))
+	require.True(t, breaker.Open(now))
+	require.True(t, breaker.Open(now.Add(59*time.Second)))
+
+	// the scrape let through after the cooldown opens it again right away if it fails
+	now = now.Add(time.Minute)
+	require.False(t, breaker.Open(now))
+	require.True(t, breaker.Record(true, 3, time.Minute, now))
+	require.True(t, breaker.Open(now))
+
+	now = now.Add(time.Minute)
+	require.False(t, breaker.Record(false, 3, time.Minute, now))
+	require.False(t, breaker.Open(now))
+}
+
+func TestCircuitBreakerHandlerRPCDown(t *testing.T) {
+	s := newTestService(t, func(*grpc.Server) {}, &exporter.ServiceConfig{
+		TendermintRPC:          "http://127.0.0.1:1",
+		CircuitBreakerFailures: 1,
+		CircuitBreakerCooldown: time.Minute,
+	})
+	handler := s.CircuitBreakerHandler(http.HandlerFunc(s.ConsensusHandler))
+
+	// /metrics/consensus only queries the RPC, its failures count like the gRPC ones
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics/consensus", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "\ncosmos_exporter_circuit_open 0\n")
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics/consensus", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "\ncosmos_exporter_circuit_open 1\n")
+}
+
+func TestCircuitBreakerHandlerSucceeded(t *testing.T) {
+	s := newValidatorsTestService(t, &emptyStakingServer{}, &exporter.ServiceConfig{
+		Limit:                  1000,
+		DenomCoefficient:       1,
+		CircuitBreakerFailures: 1,
+		CircuitBreakerCooldown: time.Minute,
+	})
+	handler := s.CircuitBreakerHandler(http.HandlerFunc(s.ValidatorsHandler))
+
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics/validators", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "\ncosmos_exporter_circuit_open 0\n")
+		require.Contains(t, recorder.Body.String(), "cosmos_validators_jailed_count 0")
+	}
+}
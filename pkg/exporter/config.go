@@ -0,0 +1,220 @@
+package exporter
+
+import (
+	"fmt"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvPrefix is prepended to the flag names to get their env vars,
+// so --denom-coefficient is COSMOS_EXPORTER_DENOM_COEFFICIENT
+const EnvPrefix = "COSMOS_EXPORTER"
+
+// EnvName returns the env var of the flag
+func EnvName(flag string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// LoadConfig sets the flags which weren't passed on the command line from their env var,
+// or else from the --config file, so the precedence is flag > env var > config file > default.
+// The config file can also have a defaults section and the per chain sections of chains,
+// the one picked with --chain overriding the defaults, see MergeConfigSections.
+func (config *ServiceConfig) LoadConfig(cmd *cobra.Command) error {
+	v := viper.New()
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+
+	configPath := config.ConfigPath
+	if flag := cmd.Flags().Lookup("config"); flag != nil && !flag.Changed {
+		configPath = v.GetString("config")
+	}
+	if configPath != "" {
+		v.SetConfigFile(configPath)
+		if err := v.ReadInConfig(); err != nil {
+			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+				return fmt.Errorf("could not read config file: %w", err)
+			}
+		}
+	}
+
+	chain := config.Chain
+	if flag := cmd.Flags().Lookup("chain"); flag != nil && !flag.Changed {
+		chain = v.GetString("chain")
+	}
+	fields, err := MergeConfigSections(cmd, v, chain)
+	if err != nil {
+		return fmt.Errorf("invalid config file %s: %w", configPath, err)
+	}
+
+	// Credits to https://carolynvanslyck.com/blog/2020/08/sting-of-the-viper/
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed || !v.IsSet(f.Name) {
+			return
+		}
+
+		value := v.Get(f.Name)
+		// lists in the config file, env vars are comma separated already
+		if values, ok := value.([]interface{}); ok {
+			strs := make([]string, len(values))
+			for i, item := range values {
+				strs[i] = fmt.Sprintf("%v", item)
+			}
+			value = strings.Join(strs, ",")
+		}
+		// maps in the config file, like the const-labels, are key=value pairs
+		if values, ok := value.(map[string]interface{}); ok {
+			strs := make([]string, 0, len(values))
+			for key, item := range values {
+				strs = append(strs, fmt.Sprintf("%s=%v", key, item))
+			}
+			sort.Strings(strs)
+			value = strings.Join(strs, ",")
+		}
+
+		if setErr := cmd.Flags().Set(f.Name, fmt.Sprintf("%v", value)); setErr != nil {
+			err = fmt.Errorf("could not set flag --%s: %w", f.Name, setErr)
+			if _, fromEnv := os.LookupEnv(EnvName(f.Name)); !fromEnv && fields[f.Name] != "" {
+				err = fmt.Errorf("invalid config file %s field %s: %w", configPath, fields[f.Name], setErr)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	config.SetBechPrefixes(cmd)
+	return config.ValidateEndpoints()
+}
+
+// MergeConfigSections merges the defaults section of the config file and the section of the chain in chains
+// on top of its top level fields, like:
+//
+//	defaults:
+//	  denom-coefficient: 1000000
+//	chains:
+//	  cosmoshub:
+//	    node: cosmoshub:9090
+//	    denom: uatom
+//	    const-labels:
+//	      network: mainnet
+//
+// Every field has to be a flag. It returns the path of the fields in the file, like chains.cosmoshub.denom,
+// to report which one is invalid.
+func MergeConfigSections(cmd *cobra.Command, v *viper.Viper, chain string) (map[string]string, error) {
+	fields := make(map[string]string)
+	merged := make(map[string]interface{})
+	merge := func(section string, values map[string]interface{}) error {
+		for key, value := range values {
+			field := key
+			if section != "" {
+				field = section + "." + key
+			}
+			if cmd.Flags().Lookup(key) == nil {
+				return fmt.Errorf("unknown field %s", field)
+			}
+			fields[key] = field
+			merged[key] = value
+		}
+		return nil
+	}
+
+	topLevel := make(map[string]interface{})
+	for key, value := range v.AllSettings() {
+		if key != "defaults" && key != "chains" {
+			topLevel[key] = value
+		}
+	}
+	if err := merge("", topLevel); err != nil {
+		return nil, err
+	}
+	if err := merge("defaults", v.GetStringMap("defaults")); err != nil {
+		return nil, err
+	}
+
+	chains := v.GetStringMap("chains")
+	if len(chains) == 0 {
+		if chain != "" {
+			return nil, fmt.Errorf("no chains section for --chain %s", chain)
+		}
+		return fields, v.MergeConfigMap(merged)
+	}
+
+	if chain == "" {
+		if len(chains) > 1 {
+			names := make([]string, 0, len(chains))
+			for name := range chains {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("pick one of the chains %s with --chain", strings.Join(names, ", "))
+		}
+		for name := range chains {
+			chain = name
+		}
+	}
+	// viper lower cases the keys
+	chain = strings.ToLower(chain)
+	if _, ok := chains[chain]; !ok {
+		return nil, fmt.Errorf("no chain %s in chains", chain)
+	}
+	if err := merge("chains."+chain, v.GetStringMap("chains."+chain)); err != nil {
+		return nil, err
+	}
+	return fields, v.MergeConfigMap(merged)
+}
+
+// ValidateEndpoints checks the node is configured with both its endpoints: --node is the gRPC one,
+// used by all the queries, and --tendermint-rpc the CometBFT RPC one, used for the chain status,
+// the consensus state and the validator set. They're often on different ports, or hosts.
+func (config *ServiceConfig) ValidateEndpoints() error {
+	// grpc also accepts target URIs, like dns:///node:9090
+	if !strings.Contains(config.NodeAddress, "://") {
+		if _, _, err := net.SplitHostPort(config.NodeAddress); err != nil {
+			return fmt.Errorf("invalid gRPC endpoint --node %q, expected host:port: %w", config.NodeAddress, err)
+		}
+	}
+
+	rpcURL, err := url.Parse(config.TendermintRPC)
+	if err != nil {
+		return fmt.Errorf("invalid RPC endpoint --tendermint-rpc %q: %w", config.TendermintRPC, err)
+	}
+	switch rpcURL.Scheme {
+	case "http", "https", "tcp", "unix":
+	default:
+		return fmt.Errorf("invalid RPC endpoint --tendermint-rpc %q, expected a http(s):// URL", config.TendermintRPC)
+	}
+	return nil
+}
+
+// Validate checks the config once the denom is set, from the flags or the node, so the misconfigurations fail at startup
+// instead of showing up as weird metric values: a zero --denom-coefficient gives +Inf in every tokens gauge
+func (config *ServiceConfig) Validate() error {
+	if config.Denom == "" {
+		return fmt.Errorf("empty denom, set it with --denom")
+	}
+	if config.DenomCoefficient <= 0 || math.IsInf(config.DenomCoefficient, 0) || math.IsNaN(config.DenomCoefficient) {
+		return fmt.Errorf("invalid denom coefficient %v, expected a positive number like 1000000, set it with --denom-coefficient or --denom-exponent", config.DenomCoefficient)
+	}
+	if config.PowerReduction == 0 {
+		return fmt.Errorf("invalid power reduction 0, expected the tokens per unit of consensus power like 1000000, set it with --power-reduction")
+	}
+	if config.RankMin < 0 || config.RankMax < 0 || (config.RankMax != 0 && config.RankMin > config.RankMax) {
+		return fmt.Errorf("invalid rank range --rank-min %d --rank-max %d", config.RankMin, config.RankMax)
+	}
+	if config.CommissionPrecision < 0 || config.CommissionPrecision > sdk.Precision {
+		return fmt.Errorf("invalid commission precision %d, expected 0 to %d decimals, set it with --commission-precision", config.CommissionPrecision, sdk.Precision)
+	}
+	if config.RecentBlocks < 0 || config.RecentBlocks > MaxRecentBlocks {
+		return fmt.Errorf("invalid --recent-blocks %d, expected at most %d", config.RecentBlocks, MaxRecentBlocks)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package exporter_test
+
+import (
+	"main/pkg/exporter"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func newConfigCommand(t *testing.T, args ...string) (*cobra.Command, *exporter.ServiceConfig) {
+	t.Helper()
+
+	config := &exporter.ServiceConfig{}
+	cmd := &cobra.Command{Use: "cosmos-exporter"}
+	config.SetCommonParameters(cmd)
+	require.NoError(t, cmd.ParseFlags(args))
+	return cmd, config
+}
+
+func TestLoadConfigEnvOverridesDefault(t *testing.T) {
+	t.Setenv(exporter.EnvName("denom-coefficient"), "1000000")
+	t.Setenv(exporter.EnvName("disabled-metrics"), "cosmos_validators_tokens,cosmos_validators_jailed")
+
+	cmd, config := newConfigCommand(t)
+	require.NoError(t, config.LoadConfig(cmd))
+	require.Equal(t, float64(1000000), config.DenomCoefficient)
+	require.Equal(t, []string{"cosmos_validators_tokens", "cosmos_validators_jailed"}, config.DisabledMetrics)
+}
+
+func TestLoadConfigFlagOverridesEnv(t *testing.T) {
+	t.Setenv(exporter.EnvName("denom-coefficient"), "1000000")
+
+	cmd, config := newConfigCommand(t, "--denom-coefficient", "1000")
+	require.NoError(t, config.LoadConfig(cmd))
+	require.Equal(t, float64(1000), config.DenomCoefficient)
+}
+
+func TestLoadConfigInvalidEnv(t *testing.T) {
+	t.Setenv(exporter.EnvName("limit"), "a lot")
+
+	cmd, config := newConfigCommand(t)
+	err := config.LoadConfig(cmd)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--limit")
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "chains.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+const chainsConfig = `
+defaults:
+  denom-coefficient: 1000000
+  limit: 500
+chains:
+  cosmoshub:
+    node: cosmoshub:9090
+    denom: uatom
+    const-labels:
+      network: mainnet
+  osmosis:
+    node: osmosis:9090
+    denom: uosmo
+    limit: 100
+`
+
+func TestLoadConfigChainSection(t *testing.T) {
+	cmd, config := newConfigCommand(t, "--config", writeConfigFile(t, chainsConfig), "--chain", "osmosis")
+	require.NoError(t, config.LoadConfig(cmd))
+	require.Equal(t, "osmosis:9090", config.NodeAddress)
+	require.Equal(t, "uosmo", config.Denom)
+	require.Equal(t, float64(1000000), config.DenomCoefficient)
+	require.Equal(t, uint64(100), config.Limit)
+
+	cmd, config = newConfigCommand(t, "--config", writeConfigFile(t, chainsConfig), "--chain", "cosmoshub")
+	require.NoError(t, config.LoadConfig(cmd))
+	require.Equal(t, uint64(500), config.Limit)
+	require.Equal(t, map[string]string{"network": "mainnet"}, config.ExtraLabels)
+}
+
+func TestLoadConfigChainSectionRequired(t *testing.T) {
+	cmd, config := newConfigCommand(t, "--config", writeConfigFile(t, chainsConfig))
+	err := config.LoadConfig(cmd)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cosmoshub, osmosis")
+}
+
+func TestLoadConfigInvalidField(t *testing.T) {
+	cmd, config := newConfigCommand(t, "--config", writeConfigFile(t, "chains:\n  osmosis:\n    denom-coeficient: 1000000\n"))
+	err := config.LoadConfig(cmd)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "chains.osmosis.denom-coeficient")
+
+	cmd, config = newConfigCommand(t, "--config", writeConfigFile(t, "defaults:\n  limit: a lot\n"))
+	err = config.LoadConfig(cmd)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "defaults.limit")
+}
+
+func TestValidate(t *testing.T) {
+	config := &exporter.ServiceConfig{Denom: "uatom", DenomCoefficient: 1000000, PowerReduction: 1000000}
+	require.NoError(t, config.Validate())
+
+	config.DenomCoefficient = 0
+	err := config.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--denom-coefficient")
+
+	config = &exporter.ServiceConfig{DenomCoefficient: 1000000, PowerReduction: 1000000}
+	err = config.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--denom")
+
+	// the consensus power would divide by it in every scrape of /metrics/validators
+	config = &exporter.ServiceConfig{Denom: "uatom", DenomCoefficient: 1000000}
+	err = config.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--power-reduction")
+}
+
+func TestValidateRankRange(t *testing.T) {
+	config := &exporter.ServiceConfig{Denom: "uatom", DenomCoefficient: 1000000, PowerReduction: 1000000, RankMin: 10, RankMax: 100}
+	require.NoError(t, config.Validate())
+	require.False(t, config.InRankBand(9))
+	require.True(t, config.InRankBand(10))
+	require.True(t, config.InRankBand(100))
+	require.False(t, config.InRankBand(101))
+
+	config.RankMax = 0
+	require.NoError(t, config.Validate())
+	require.True(t, config.InRankBand(1000))
+
+	config.RankMax = 5
+	err := config.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--rank-min")
+}
+
+func TestValidateRecentBlocks(t *testing.T) {
+	config := &exporter.ServiceConfig{Denom: "uatom", DenomCoefficient: 1000000, PowerReduction: 1000000, RecentBlocks: exporter.MaxRecentBlocks}
+	require.NoError(t, config.Validate())
+
+	config.RecentBlocks = exporter.MaxRecentBlocks + 1
+	err := config.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--recent-blocks")
+
+	config.RecentBlocks = -1
+	require.Error(t, config.Validate())
+}
+
+func TestValidateCommissionPrecision(t *testing.T) {
+	config := &exporter.ServiceConfig{Denom: "uatom", DenomCoefficient: 1000000, PowerReduction: 1000000, CommissionPrecision: 4}
+	require.NoError(t, config.Validate())
+
+	config.CommissionPrecision = -1
+	err := config.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--commission-precision")
+
+	config.CommissionPrecision = 19
+	require.Error(t, config.Validate())
+}
@@ -0,0 +1,319 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	crytpocode "github.com/cosmos/cosmos-sdk/crypto/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type ConsensusMetrics struct {
+	heightGauge   prometheus.Gauge
+	roundGauge    prometheus.Gauge
+	stepGauge     prometheus.Gauge
+	hasVotedGauge *prometheus.GaugeVec
+}
+
+func NewConsensusMetrics(reg prometheus.Registerer, config *ServiceConfig) *ConsensusMetrics {
+	m := &ConsensusMetrics{
+		heightGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_consensus_height",
+				Help:        "Height the node is reaching consensus on",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		roundGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_consensus_round",
+				Help:        "Consensus round of the current height, staying high when the validators can't agree on a block",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		stepGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_consensus_step",
+				Help:        "Consensus step of the current round: 1 new height, 2 new round, 3 propose, 4 prevote, 5 prevote wait, 6 precommit, 7 precommit wait, 8 commit",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		hasVotedGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_has_voted_current_round",
+				Help:        "1 if the vote of the Cosmos-based blockchain validator is in the vote set of the current consensus round, 0 if no",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker", "vote"},
+		),
+	}
+	reg.MustRegister(m.heightGauge)
+	reg.MustRegister(m.roundGauge)
+	reg.MustRegister(m.stepGauge)
+	reg.MustRegister(m.hasVotedGauge)
+	return m
+}
+func GetConsensusMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *ConsensusMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying consensus state")
+		queryStart := time.Now()
+
+		cs, err := s.chainStatus()
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get chain status")
+			return
+		}
+
+		state, err := cs.ConsensusState()
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get consensus state")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying consensus state")
+
+		metrics.heightGauge.Set(float64(state.Height))
+		metrics.roundGauge.Set(float64(state.Round))
+		metrics.stepGauge.Set(float64(state.Step))
+
+		if !config.MetricEnabled("cosmos_validators_has_voted_current_round") {
+			return
+		}
+
+		// the votes are indexed like the validator set of the height
+		validatorSet, err := cs.ValidatorSet(state.Height)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get validator set")
+			return
+		}
+
+		validators, err := s.GetValidators()
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get validators")
+			return
+		}
+		validatorsByConsAddress := ValidatorsByConsAddress(sublogger, validators)
+
+		for vote, voted := range map[string][]bool{"prevote": state.Prevotes, "precommit": state.Precommits} {
+			if len(voted) != len(validatorSet) {
+				// the validator set changed in between the queries
+				sublogger.Debug().
+					Str("vote", vote).
+					Int("votes", len(voted)).
+					Int("validators", len(validatorSet)).
+					Msg("Vote set doesn't match the validator set")
+				continue
+			}
+
+			for index, consensusValidator := range validatorSet {
+				validator, ok := validatorsByConsAddress[consensusValidator.Address]
+				if !ok {
+					continue
+				}
+
+				// golang doesn't have a ternary operator, so we have to stick with this ugly solution
+				var hasVoted float64
+
+				if voted[index] {
+					hasVoted = 1
+				} else {
+					hasVoted = 0
+				}
+				metrics.hasVotedGauge.With(prometheus.Labels{
+					"address": validator.OperatorAddress,
+					"moniker": validator.Description.Moniker,
+					"vote":    vote,
+				}).Set(hasVoted)
+			}
+		}
+	}()
+
+}
+
+// ConsensusState is the part of the round state of the node we expose,
+// with the validators which voted in the current round by their index in the validator set
+type ConsensusState struct {
+	Height     int64
+	Round      int64
+	Step       int64
+	Prevotes   []bool
+	Precommits []bool
+}
+
+// ConsensusState returns the round state from the /consensus_state RPC endpoint. Only the fields
+// we need are decoded, as the rest of the payload is large and changes between CometBFT versions.
+func (cs ChainStatus) ConsensusState() (ConsensusState, error) {
+	result, err := cs.client.ConsensusState(context.Background())
+	cs.queries.record(err)
+	if err != nil {
+		return ConsensusState{}, err
+	}
+
+	var roundState struct {
+		HeightRoundStep string `json:"height/round/step"`
+		Votes           []struct {
+			Round              int64  `json:"round"`
+			PrevotesBitArray   string `json:"prevotes_bit_array"`
+			PrecommitsBitArray string `json:"precommits_bit_array"`
+		} `json:"votes"`
+	}
+	if err := json.Unmarshal(result.RoundState, &roundState); err != nil {
+		return ConsensusState{}, err
+	}
+
+	state, err := parseHeightRoundStep(roundState.HeightRoundStep)
+	if err != nil {
+		return ConsensusState{}, err
+	}
+
+	for _, votes := range roundState.Votes {
+		if votes.Round != state.Round {
+			continue
+		}
+		if state.Prevotes, err = parseBitArray(votes.PrevotesBitArray); err != nil {
+			return ConsensusState{}, err
+		}
+		if state.Precommits, err = parseBitArray(votes.PrecommitsBitArray); err != nil {
+			return ConsensusState{}, err
+		}
+	}
+	return state, nil
+}
+
+// ConsensusValidator is a validator of the CometBFT validator set
+type ConsensusValidator struct {
+	// bech32 consensus address
+	Address     string
+	VotingPower int64
+}
+
+// ValidatorSet returns the CometBFT validator set of the height, in the order of the votes
+func (cs ChainStatus) ValidatorSet(height int64) ([]ConsensusValidator, error) {
+	var validators []ConsensusValidator
+	// the max page size of the RPC
+	perPage := 100
+	for page := 1; ; page++ {
+		result, err := cs.client.Validators(context.Background(), &height, &page, &perPage)
+		cs.queries.record(err)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, validator := range result.Validators {
+			validators = append(validators, ConsensusValidator{
+				Address:     sdk.ConsAddress(validator.Address).String(),
+				VotingPower: validator.VotingPower,
+			})
+		}
+		if len(result.Validators) == 0 || len(validators) >= result.Total {
+			return validators, nil
+		}
+	}
+}
+
+// ValidatorsByConsAddress indexes the staking validators by their bech32 consensus address, to match them with the CometBFT ones
+func ValidatorsByConsAddress(sublogger *zerolog.Logger, validators []stakingtypes.Validator) map[string]stakingtypes.Validator {
+	interfaceRegistry := codectypes.NewInterfaceRegistry()
+	crytpocode.RegisterInterfaces(interfaceRegistry)
+
+	validatorsByConsAddress := make(map[string]stakingtypes.Validator, len(validators))
+	for _, validator := range validators {
+		if err := validator.UnpackInterfaces(interfaceRegistry); err != nil {
+			sublogger.Error().
+				Str("address", validator.OperatorAddress).
+				Err(err).
+				Msg("Could not get unpack validator inferfaces")
+			continue
+		}
+		consAddress, err := ConsAddress(validator)
+		if err != nil {
+			sublogger.Error().
+				Str("address", validator.OperatorAddress).
+				Err(err).
+				Msg("Could not get validator pubkey")
+			continue
+		}
+		validatorsByConsAddress[consAddress.String()] = validator
+	}
+	return validatorsByConsAddress
+}
+
+// parseBitArray parses the vote bit arrays of the round state, like "BA{4:xx_x} 3/4 = 0.75"
+func parseBitArray(bitArray string) ([]bool, error) {
+	if bitArray == "nil-BitArray" {
+		return nil, nil
+	}
+
+	start := strings.Index(bitArray, ":")
+	end := strings.Index(bitArray, "}")
+	if !strings.HasPrefix(bitArray, "BA{") || start < 0 || end < start {
+		return nil, fmt.Errorf("unexpected bit array %q", bitArray)
+	}
+
+	bits := make([]bool, 0, end-start-1)
+	for _, bit := range bitArray[start+1 : end] {
+		switch bit {
+		case 'x':
+			bits = append(bits, true)
+		case '_':
+			bits = append(bits, false)
+		}
+	}
+	return bits, nil
+}
+
+// parseHeightRoundStep parses the "height/round/step" field of the round state, like 12345/0/3
+func parseHeightRoundStep(heightRoundStep string) (ConsensusState, error) {
+	parts := strings.Split(heightRoundStep, "/")
+	if len(parts) != 3 {
+		return ConsensusState{}, fmt.Errorf("unexpected height/round/step %q", heightRoundStep)
+	}
+
+	var values [3]int64
+	for i, part := range parts {
+		value, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return ConsensusState{}, fmt.Errorf("unexpected height/round/step %q: %w", heightRoundStep, err)
+		}
+		values[i] = value
+	}
+	return ConsensusState{Height: values[0], Round: values[1], Step: values[2]}, nil
+}
+func (s *Service) ConsensusHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/consensus")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	consensusMetrics := NewConsensusMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetConsensusMetrics(&wg, &sublogger, consensusMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/consensus").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
@@ -0,0 +1,116 @@
+package exporter
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ConsensusParamsMetrics struct {
+	maxGasGauge   prometheus.Gauge
+	maxBytesGauge prometheus.Gauge
+}
+
+func NewConsensusParamsMetrics(reg prometheus.Registerer, config *ServiceConfig) *ConsensusParamsMetrics {
+	m := &ConsensusParamsMetrics{
+		maxGasGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_consensus_max_gas",
+				Help:        "Maximum gas of a block from the x/consensus module params, -1 if unlimited",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		maxBytesGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_consensus_max_bytes",
+				Help:        "Maximum size of a block in bytes from the x/consensus module params",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+	}
+	reg.MustRegister(m.maxGasGauge)
+	reg.MustRegister(m.maxBytesGauge)
+	return m
+}
+func GetConsensusParamsMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *ConsensusParamsMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying consensus params")
+		queryStart := time.Now()
+
+		// x/consensus is only there from SDK v0.47, QueryParamsRequest is empty and
+		// QueryParamsResponse { tendermint.types.ConsensusParams params = 1; }
+		response, err := s.rawQuery(context.Background(), "/cosmos.consensus.v1.Query/Params", nil)
+		if status.Code(err) == codes.Unimplemented {
+			// the older chains still have them in the x/params module
+			sublogger.Debug().Err(err).Msg("Chain has no x/consensus module, not serving consensus params")
+			return
+		}
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get consensus params")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying consensus params")
+
+		// ConsensusParams { BlockParams block = 1; ... }, BlockParams { int64 max_bytes = 1; int64 max_gas = 2; }
+		params, err := rawBytesField(response, 1)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not decode consensus params")
+			return
+		}
+		block, err := rawBytesField(params, 1)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not decode consensus block params")
+			return
+		}
+		maxBytes, err := rawVarintField(block, 1)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not decode consensus max bytes")
+			return
+		}
+		maxGas, err := rawVarintField(block, 2)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not decode consensus max gas")
+			return
+		}
+
+		// int64 varints, the unlimited -1 max gas is sent as its two's complement
+		metrics.maxBytesGauge.Set(float64(int64(maxBytes)))
+		metrics.maxGasGauge.Set(float64(int64(maxGas)))
+	}()
+
+}
+func (s *Service) ConsensusParamsHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/consensus-params")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	consensusParamsMetrics := NewConsensusParamsMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetConsensusParamsMetrics(&wg, &sublogger, consensusParamsMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/consensus-params").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
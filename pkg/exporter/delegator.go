@@ -5,30 +5,38 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	querytypes "github.com/cosmos/cosmos-sdk/types/query"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
-	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
+// updateDelegatorCount records the number of delegators of the validator and returns its change since the previous scrape,
+// ok is false on the first sample as there is nothing to compare it with yet
+func (s *Service) updateDelegatorCount(address string, count int) (delta int, ok bool) {
+	s.delegatorCountsMutex.Lock()
+	defer s.delegatorCountsMutex.Unlock()
+
+	if s.delegatorCounts == nil {
+		s.delegatorCounts = make(map[string]int)
+	}
+
+	previous, ok := s.delegatorCounts[address]
+	s.delegatorCounts[address] = count
+	return count - previous, ok
+}
 func (s *Service) DelegatorHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/delegator")
 	requestStart := time.Now()
 
 	sublogger := s.Log.With().
-		Str("request-id", uuid.New().String()).
+		Str("request-id", RequestID(r)).
 		Logger()
 
-	validatorAddress := r.URL.Query().Get("validator_address")
-	valAddress, err := sdk.ValAddressFromBech32(validatorAddress)
-	if err != nil {
-		sublogger.Error().
-			Str("validator_address", validatorAddress).
-			Err(err).
-			Msg("Could not get validator address")
-		return
-	}
+	// validator_address can be a comma separated list of validators
+	validatorAddresses := r.URL.Query().Get("validator_address")
 
 	delegatorTotalGauge := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -39,54 +47,125 @@ func (s *Service) DelegatorHandler(w http.ResponseWriter, r *http.Request) {
 		[]string{"validator_address"},
 	)
 
-	registry := prometheus.NewRegistry()
+	delegatorCountDeltaGauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "cosmos_validator_delegator_count_delta",
+			Help:        "Change of the number of delegators in validator since the previous scrape",
+			ConstLabels: s.Config.ConstLabels,
+		},
+		[]string{"validator_address"},
+	)
+
+	delegationSizeHistogram := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "cosmos_validator_delegation_size",
+			Help:        "Distribution of the delegations to the validator, in display denom",
+			ConstLabels: s.Config.ConstLabels,
+			Buckets:     prometheus.ExponentialBuckets(1, 10, 9),
+		},
+		[]string{"validator_address"},
+	)
+
+	registry := s.Config.NewRegistry()
 	registry.MustRegister(delegatorTotalGauge)
+	registry.MustRegister(delegatorCountDeltaGauge)
+	if s.Config.DelegationSizes {
+		registry.MustRegister(delegationSizeHistogram)
+	}
 
 	var wg sync.WaitGroup
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		sublogger.Debug().
-			Str("validator_address", validatorAddress).
-			Msg("Started querying delegator")
-		queryStart := time.Now()
-
-		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
-		delegatorRes, err := stakingClient.ValidatorDelegations(
-			context.Background(),
-			&stakingtypes.QueryValidatorDelegationsRequest{
-				ValidatorAddr: valAddress.String(),
-				Pagination: &querytypes.PageRequest{
-					Limit: s.Config.Limit,
-				},
-			},
-		)
+	for _, validatorAddress := range strings.Split(validatorAddresses, ",") {
+		validatorAddress = strings.TrimSpace(validatorAddress)
+		if validatorAddress == "" {
+			continue
+		}
+
+		valAddress, err := sdk.ValAddressFromBech32(validatorAddress)
 		if err != nil {
-			sublogger.Error().
+			sublogger.Warn().
 				Str("validator_address", validatorAddress).
 				Err(err).
-				Msg("Could not get delegator")
-			return
+				Msg("Could not get validator address, skipping it")
+			continue
 		}
 
-		sublogger.Debug().
-			Str("validator_address", validatorAddress).
-			Float64("request-time", time.Since(queryStart).Seconds()).
-			Msg("Finished querying delegators")
+		wg.Add(1)
+		go func(validatorAddress string, valAddress sdk.ValAddress) {
+			defer wg.Done()
+			sublogger.Debug().
+				Str("validator_address", validatorAddress).
+				Msg("Started querying delegator")
+			queryStart := time.Now()
+
+			stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
 
-		delegatorTotalGauge.With(prometheus.Labels{
-			"validator_address": validatorAddress,
-		}).Set(float64(len(delegatorRes.DelegationResponses)))
-	}()
+			// the validators with more delegators than --limit need several pages
+			delegations, err := Paginate(func(key []byte) ([]stakingtypes.DelegationResponse, []byte, error) {
+				delegatorRes, err := stakingClient.ValidatorDelegations(
+					context.Background(),
+					&stakingtypes.QueryValidatorDelegationsRequest{
+						ValidatorAddr: valAddress.String(),
+						Pagination: &querytypes.PageRequest{
+							Key:   key,
+							Limit: s.Config.Limit,
+						},
+					},
+				)
+				if err != nil {
+					return nil, nil, err
+				}
+				return delegatorRes.DelegationResponses, delegatorRes.Pagination.GetNextKey(), nil
+			})
+			if err != nil {
+				sublogger.Error().
+					Str("validator_address", validatorAddress).
+					Err(err).
+					Msg("Could not get delegator")
+				return
+			}
+
+			sublogger.Debug().
+				Str("validator_address", validatorAddress).
+				Float64("request-time", time.Since(queryStart).Seconds()).
+				Int("delegationsLength", len(delegations)).
+				Msg("Finished querying delegators")
+
+			delegatorTotalGauge.With(prometheus.Labels{
+				"validator_address": validatorAddress,
+			}).Set(float64(len(delegations)))
+
+			if delta, ok := s.updateDelegatorCount(validatorAddress, len(delegations)); ok {
+				delegatorCountDeltaGauge.With(prometheus.Labels{
+					"validator_address": validatorAddress,
+				}).Set(float64(delta))
+			}
+
+			if !s.Config.DelegationSizes {
+				return
+			}
+			for _, delegation := range delegations {
+				// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+				if value, err := strconv.ParseFloat(delegation.Balance.Amount.String(), 64); err != nil {
+					sublogger.Error().
+						Str("validator_address", validatorAddress).
+						Err(err).
+						Msg("Could not parse delegation balance")
+				} else {
+					delegationSizeHistogram.With(prometheus.Labels{
+						"validator_address": validatorAddress,
+					}).Observe(value / s.Config.DenomCoefficient)
+				}
+			}
+		}(validatorAddress, valAddress)
+	}
 
 	wg.Wait()
 
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	h.ServeHTTP(w, r)
+	s.ServeMetrics(w, r, registry)
 	sublogger.Info().
 		Str("method", "GET").
-		Str("endpoint", "/metrics/delegator?validator_address="+validatorAddress).
+		Str("endpoint", "/metrics/delegator?validator_address="+validatorAddresses).
 		Float64("request-time", time.Since(requestStart).Seconds()).
 		Msg("Request processed")
 }
@@ -0,0 +1,154 @@
+package exporter
+
+import (
+	"context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type DelegatorDelegationsMetrics struct {
+	delegationGauge     *prometheus.GaugeVec
+	totalDelegatedGauge *prometheus.GaugeVec
+}
+
+func NewDelegatorDelegationsMetrics(reg prometheus.Registerer, config *ServiceConfig) *DelegatorDelegationsMetrics {
+	m := &DelegatorDelegationsMetrics{
+		delegationGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_delegator_delegation",
+				Help:        "Tokens delegated by the delegator to the validator",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"delegator_address", "validator_address", "denom"},
+		),
+		totalDelegatedGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_delegator_total_delegated",
+				Help:        "Tokens delegated by the delegator to all the validators",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"delegator_address", "denom"},
+		),
+	}
+	reg.MustRegister(m.delegationGauge)
+	reg.MustRegister(m.totalDelegatedGauge)
+	return m
+}
+func GetDelegatorDelegationsMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *DelegatorDelegationsMetrics, s *Service, config *ServiceConfig, address sdk.AccAddress) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().
+			Str("delegator_address", address.String()).
+			Msg("Started querying delegator delegations")
+		queryStart := time.Now()
+
+		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
+
+		// the delegators spreading their stake can have more delegations than --limit
+		delegations, err := Paginate(func(key []byte) ([]stakingtypes.DelegationResponse, []byte, error) {
+			stakingRes, err := stakingClient.DelegatorDelegations(
+				context.Background(),
+				&stakingtypes.QueryDelegatorDelegationsRequest{
+					DelegatorAddr: address.String(),
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return stakingRes.DelegationResponses, stakingRes.Pagination.GetNextKey(), nil
+		})
+		if err != nil {
+			sublogger.Error().
+				Str("delegator_address", address.String()).
+				Err(err).
+				Msg("Could not get delegator delegations")
+			return
+		}
+
+		sublogger.Debug().
+			Str("delegator_address", address.String()).
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int("delegationsLength", len(delegations)).
+			Msg("Finished querying delegator delegations")
+
+		total := sdk.ZeroInt()
+		for _, delegation := range delegations {
+			total = total.Add(delegation.Balance.Amount)
+
+			// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+			if value, err := strconv.ParseFloat(delegation.Balance.Amount.String(), 64); err != nil {
+				sublogger.Error().
+					Str("delegator_address", address.String()).
+					Err(err).
+					Msg("Could not parse delegation")
+			} else {
+				metrics.delegationGauge.With(prometheus.Labels{
+					"delegator_address": address.String(),
+					"validator_address": delegation.Delegation.ValidatorAddress,
+					"denom":             config.Denom,
+				}).Set(value / config.DenomCoefficient)
+			}
+		}
+
+		// summed as ints, so the total doesn't add up the rounding of every delegation
+		if value, err := strconv.ParseFloat(total.String(), 64); err != nil {
+			sublogger.Error().
+				Str("delegator_address", address.String()).
+				Err(err).
+				Msg("Could not parse total delegated")
+		} else {
+			metrics.totalDelegatedGauge.With(prometheus.Labels{
+				"delegator_address": address.String(),
+				"denom":             config.Denom,
+			}).Set(value / config.DenomCoefficient)
+		}
+	}()
+
+}
+func (s *Service) DelegatorDelegationsHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/delegator-delegations")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	address := r.URL.Query().Get("delegator_address")
+	delegatorAddress, err := sdk.AccAddressFromBech32(address)
+	if err != nil {
+		sublogger.Error().
+			Str("delegator_address", address).
+			Err(err).
+			Msg("Could not get delegator address")
+		return
+	}
+
+	registry := s.Config.NewRegistry()
+	delegatorDelegationsMetrics := NewDelegatorDelegationsMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetDelegatorDelegationsMetrics(&wg, &sublogger, delegatorDelegationsMetrics, s, s.Config, delegatorAddress)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/delegator-delegations?delegator_address="+address).
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
@@ -0,0 +1,111 @@
+package exporter
+
+import (
+	"context"
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type DenomMetadataMetrics struct {
+	denomMetadataGauge *prometheus.GaugeVec
+}
+
+func NewDenomMetadataMetrics(reg prometheus.Registerer, config *ServiceConfig) *DenomMetadataMetrics {
+	m := &DenomMetadataMetrics{
+		denomMetadataGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_denom_metadata",
+				Help:        "Bank metadata of the denom, always 1. exponent is the one of the display unit",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"base", "display", "symbol", "exponent"},
+		),
+	}
+	reg.MustRegister(m.denomMetadataGauge)
+	return m
+}
+func GetDenomMetadataMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *DenomMetadataMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying denoms metadata")
+		queryStart := time.Now()
+
+		bankClient := banktypes.NewQueryClient(s.GrpcConn)
+
+		metadatas, err := Paginate(func(key []byte) ([]banktypes.Metadata, []byte, error) {
+			denomsResponse, err := bankClient.DenomsMetadata(
+				context.Background(),
+				&banktypes.QueryDenomsMetadataRequest{
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return denomsResponse.Metadatas, denomsResponse.Pagination.GetNextKey(), nil
+		})
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get denoms metadata")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int("metadatasLength", len(metadatas)).
+			Msg("Finished querying denoms metadata")
+
+		for _, metadata := range metadatas {
+			// the exponent can't be known if the display unit is missing
+			exponent := ""
+			for _, unit := range metadata.DenomUnits {
+				if unit.Denom == metadata.Display {
+					exponent = strconv.FormatUint(uint64(unit.Exponent), 10)
+					break
+				}
+			}
+
+			metrics.denomMetadataGauge.With(prometheus.Labels{
+				"base":     metadata.Base,
+				"display":  metadata.Display,
+				"symbol":   metadata.Symbol,
+				"exponent": exponent,
+			}).Set(1)
+		}
+	}()
+
+}
+func (s *Service) DenomMetadataHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/denom-metadata")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	denomMetadataMetrics := NewDenomMetadataMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetDenomMetadataMetrics(&wg, &sublogger, denomMetadataMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/denom-metadata").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
@@ -0,0 +1,163 @@
+package exporter
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type EpochsMetrics struct {
+	epochNumberGauge      *prometheus.GaugeVec
+	secondsUntilNextGauge *prometheus.GaugeVec
+	epochStartHeightGauge *prometheus.GaugeVec
+}
+
+func NewEpochsMetrics(reg prometheus.Registerer, config *ServiceConfig) *EpochsMetrics {
+	m := &EpochsMetrics{
+		epochNumberGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_epoch_number",
+				Help:        "Current epoch number",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"identifier"},
+		),
+		secondsUntilNextGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_epoch_seconds_until_next",
+				Help:        "Seconds until the next epoch starts",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"identifier"},
+		),
+		epochStartHeightGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_epoch_start_height",
+				Help:        "Height the current epoch started at",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"identifier"},
+		),
+	}
+	reg.MustRegister(m.epochNumberGauge)
+	reg.MustRegister(m.secondsUntilNextGauge)
+	reg.MustRegister(m.epochStartHeightGauge)
+	return m
+}
+func GetEpochsMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *EpochsMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying epochs")
+		queryStart := time.Now()
+
+		epochs, err := s.GetEpochs()
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get epochs")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int("epochsLength", len(epochs)).
+			Msg("Finished querying epochs")
+
+		for _, epoch := range epochs {
+			metrics.epochNumberGauge.With(prometheus.Labels{
+				"identifier": epoch.Identifier,
+			}).Set(float64(epoch.CurrentEpoch))
+			metrics.secondsUntilNextGauge.With(prometheus.Labels{
+				"identifier": epoch.Identifier,
+			}).Set(time.Until(epoch.CurrentEpochStartTime.Add(epoch.Duration)).Seconds())
+			metrics.epochStartHeightGauge.With(prometheus.Labels{
+				"identifier": epoch.Identifier,
+			}).Set(float64(epoch.CurrentEpochStartHeight))
+		}
+	}()
+
+}
+
+// EpochInfo is the part of the osmosis x/epochs EpochInfo we expose
+type EpochInfo struct {
+	Identifier              string
+	Duration                time.Duration
+	CurrentEpoch            int64
+	CurrentEpochStartTime   time.Time
+	CurrentEpochStartHeight int64
+}
+
+// GetEpochs returns the epochs of the x/epochs module, used by osmosis and the chains forked from it
+func (s *Service) GetEpochs() ([]EpochInfo, error) {
+	response, err := s.rawQuery(context.Background(), "/osmosis.epochs.v1beta1.Query/EpochInfos", nil)
+	if err != nil {
+		return nil, err
+	}
+	// QueryEpochsInfoResponse: repeated EpochInfo epochs = 1
+	messages, err := rawBytesFields(response, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	epochs := make([]EpochInfo, 0, len(messages))
+	for _, message := range messages {
+		// EpochInfo: string identifier = 1, Duration duration = 3, int64 current_epoch = 4,
+		// Timestamp current_epoch_start_time = 5, int64 current_epoch_start_height = 8
+		identifier, err := rawBytesField(message, 1)
+		if err != nil {
+			return nil, err
+		}
+		duration, err := rawDurationField(message, 3)
+		if err != nil {
+			return nil, err
+		}
+		currentEpoch, err := rawVarintField(message, 4)
+		if err != nil {
+			return nil, err
+		}
+		currentEpochStartTime, err := rawTimeField(message, 5)
+		if err != nil {
+			return nil, err
+		}
+		currentEpochStartHeight, err := rawVarintField(message, 8)
+		if err != nil {
+			return nil, err
+		}
+
+		epochs = append(epochs, EpochInfo{
+			Identifier:              string(identifier),
+			Duration:                duration,
+			CurrentEpoch:            int64(currentEpoch),
+			CurrentEpochStartTime:   currentEpochStartTime,
+			CurrentEpochStartHeight: int64(currentEpochStartHeight),
+		})
+	}
+	return epochs, nil
+}
+func (s *Service) EpochsHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/epochs")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	epochsMetrics := NewEpochsMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetEpochsMetrics(&wg, &sublogger, epochsMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/epochs").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
@@ -0,0 +1,11 @@
+package exporter
+
+// the raw protobuf decoders are only used by the handlers, exported here for the tests of exporter_test
+var (
+	RawBytesFields   = rawBytesFields
+	RawBytesField    = rawBytesField
+	RawVarintField   = rawVarintField
+	RawDecField      = rawDecField
+	RawTimeField     = rawTimeField
+	RawDurationField = rawDurationField
+)
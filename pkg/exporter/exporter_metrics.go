@@ -0,0 +1,222 @@
+package exporter
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// Version and Commit are set at build time, with -ldflags "-X main/pkg/exporter.Version=v1.0.0 -X main/pkg/exporter.Commit=abc123"
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+type ExporterMetrics struct {
+	queryErrorsCounter       *prometheus.CounterVec
+	grpcQueriesCounter       *prometheus.CounterVec
+	grpcConnectionStateGauge prometheus.Gauge
+	buildInfoGauge           *prometheus.GaugeVec
+	circuitOpenGauge         prometheus.Gauge
+}
+
+func NewExporterMetrics(reg prometheus.Registerer, config *ServiceConfig) *ExporterMetrics {
+	m := &ExporterMetrics{
+		queryErrorsCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "cosmos_exporter_query_errors_total",
+				Help:        "gRPC queries to the node which failed since the exporter started, by module",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"module"},
+		),
+		grpcQueriesCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "cosmos_exporter_grpc_queries_total",
+				Help:        "gRPC queries sent to the node since the exporter started, by the metrics endpoint which needed them",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"endpoint"},
+		),
+		grpcConnectionStateGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_exporter_grpc_connection_state",
+				Help:        "State of the gRPC connection to the node: 0 idle, 1 connecting, 2 ready, 3 transient failure, 4 shutdown",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		buildInfoGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_exporter_build_info",
+				Help:        "Build of the exporter, always 1",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"version", "commit", "go_version"},
+		),
+		circuitOpenGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_exporter_circuit_open",
+				Help:        "1 if the scrapes don't query the node after too many failed ones, 0 otherwise",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+	}
+	reg.MustRegister(m.queryErrorsCounter)
+	reg.MustRegister(m.grpcQueriesCounter)
+	reg.MustRegister(m.grpcConnectionStateGauge)
+	reg.MustRegister(m.buildInfoGauge)
+	reg.MustRegister(m.circuitOpenGauge)
+	return m
+}
+
+// BuildCommit returns the commit set with -ldflags, or else the one go build stamps the binaries built from a git checkout with
+func BuildCommit() string {
+	if Commit != "" {
+		return Commit
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				return setting.Value
+			}
+		}
+	}
+	return "unknown"
+}
+
+// ClientConn is the gRPC connection to the node, a *grpc.ClientConn wrapped to count the queries of every endpoint
+type ClientConn interface {
+	grpc.ClientConnInterface
+	GetState() connectivity.State
+	Close() error
+}
+
+// endpointConn counts the gRPC queries sent for an endpoint, and their results for the scrape sending them
+type endpointConn struct {
+	ClientConn
+	endpoint string
+	state    *state
+	queries  *scrapeQueries
+}
+
+func (c endpointConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	c.state.grpcQueriesMutex.Lock()
+	if c.state.grpcQueries == nil {
+		c.state.grpcQueries = make(map[string]float64)
+	}
+	c.state.grpcQueries[c.endpoint]++
+	c.state.grpcQueriesMutex.Unlock()
+
+	err := c.ClientConn.Invoke(ctx, method, args, reply, opts...)
+	c.queries.record(err)
+	return err
+}
+
+// ForEndpoint returns a copy of the service counting its gRPC queries as sent for the endpoint, and their results
+// for the circuit breaker as the ones of the request. The copy shares the state of the service, the queries of the
+// cached data are counted for the endpoint which refreshed it.
+func (s *Service) ForEndpoint(r *http.Request, endpoint string) *Service {
+	endpointService := *s
+	endpointService.queries, _ = r.Context().Value(scrapeQueriesKey{}).(*scrapeQueries)
+	if conn, ok := s.GrpcConn.(endpointConn); ok {
+		// counting the queries once if the handlers are nested, like in single mode
+		endpointService.GrpcConn = conn.ClientConn
+	}
+	endpointService.GrpcConn = endpointConn{
+		ClientConn: endpointService.GrpcConn,
+		endpoint:   endpoint,
+		state:      s.state,
+		queries:    endpointService.queries,
+	}
+	return &endpointService
+}
+
+// background returns a copy of the service whose queries aren't counted for the circuit breaker as the ones of the
+// request, for the refreshes outliving it
+func (s *Service) background() *Service {
+	backgroundService := *s
+	backgroundService.queries = nil
+	if conn, ok := s.GrpcConn.(endpointConn); ok {
+		conn.queries = nil
+		backgroundService.GrpcConn = conn
+	}
+	return &backgroundService
+}
+
+// queryErrorsInterceptor counts the failed queries of every module, whichever handler sent them
+func (s *Service) queryErrorsInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err != nil {
+		s.queryErrorsMutex.Lock()
+		if s.queryErrors == nil {
+			s.queryErrors = make(map[string]float64)
+		}
+		s.queryErrors[QueryModule(method)]++
+		s.queryErrorsMutex.Unlock()
+	}
+	return err
+}
+
+// QueryModule returns the module of a gRPC method, like staking for /cosmos.staking.v1beta1.Query/Validators
+// or transfer for /ibc.applications.transfer.v1.Query/DenomTrace
+func QueryModule(method string) string {
+	service := strings.TrimPrefix(method, "/")
+	if i := strings.Index(service, "/"); i >= 0 {
+		service = service[:i]
+	}
+
+	// dropping the service name, then the version if the package has one
+	parts := strings.Split(service, ".")
+	if len(parts) > 1 {
+		parts = parts[:len(parts)-1]
+	}
+	if last := parts[len(parts)-1]; len(parts) > 1 && len(last) > 1 && last[0] == 'v' && last[1] >= '0' && last[1] <= '9' {
+		parts = parts[:len(parts)-1]
+	}
+	return parts[len(parts)-1]
+}
+
+// exporterRegistry returns the metrics about the exporter itself which persist between scrapes, served by every handler
+func (s *Service) exporterRegistry() *Registry {
+	registry := s.Config.NewRegistry()
+	exporterMetrics := NewExporterMetrics(registry, s.Config)
+
+	// the connectivity states are numbered in this order
+	exporterMetrics.grpcConnectionStateGauge.Set(float64(s.GrpcConn.GetState()))
+	exporterMetrics.buildInfoGauge.With(prometheus.Labels{
+		"version":    Version,
+		"commit":     BuildCommit(),
+		"go_version": runtime.Version(),
+	}).Set(1)
+
+	// golang doesn't have a ternary operator, so we have to stick with this ugly solution
+	var circuitOpen float64
+	if s.circuitBreaker.Open(time.Now()) {
+		circuitOpen = 1
+	}
+	exporterMetrics.circuitOpenGauge.Set(circuitOpen)
+
+	s.queryErrorsMutex.Lock()
+	defer s.queryErrorsMutex.Unlock()
+	for module, total := range s.queryErrors {
+		exporterMetrics.queryErrorsCounter.With(prometheus.Labels{
+			"module": module,
+		}).Add(total)
+	}
+
+	s.grpcQueriesMutex.Lock()
+	defer s.grpcQueriesMutex.Unlock()
+	for endpoint, total := range s.grpcQueries {
+		exporterMetrics.grpcQueriesCounter.With(prometheus.Labels{
+			"endpoint": endpoint,
+		}).Add(total)
+	}
+	return registry
+}
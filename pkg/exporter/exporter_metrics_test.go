@@ -0,0 +1,26 @@
+package exporter_test
+
+import (
+	"main/pkg/exporter"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryModule(t *testing.T) {
+	tests := []struct {
+		Method string
+		Module string
+	}{
+		{Method: "/cosmos.staking.v1beta1.Query/Validators", Module: "staking"},
+		{Method: "/cosmos.base.tendermint.v1beta1.Service/GetNodeInfo", Module: "tendermint"},
+		{Method: "/ibc.applications.transfer.v1.Query/DenomTrace", Module: "transfer"},
+		{Method: "/kujira.oracle.Query/ExchangeRates", Module: "oracle"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Method, func(t *testing.T) {
+			require.Equal(t, test.Module, exporter.QueryModule(test.Method))
+		})
+	}
+}
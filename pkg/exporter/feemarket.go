@@ -0,0 +1,172 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fee market modules which can be passed to --feemarket
+const (
+	FeeMarketSkip    = "skip"    // skip-mev/feemarket
+	FeeMarketOsmosis = "osmosis" // osmosis x/txfees EIP-1559
+	FeeMarketEvmos   = "evmos"   // ethermint/evmos x/feemarket
+)
+
+type FeeMarketMetrics struct {
+	baseFeeGauge *prometheus.GaugeVec
+}
+
+func NewFeeMarketMetrics(reg prometheus.Registerer, config *ServiceConfig) *FeeMarketMetrics {
+	m := &FeeMarketMetrics{
+		baseFeeGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_feemarket_base_fee",
+				Help:        "Current base fee of the fee market module, per gas unit",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"denom"},
+		),
+	}
+	reg.MustRegister(m.baseFeeGauge)
+	return m
+}
+func GetFeeMarketMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *FeeMarketMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Str("feemarket", config.FeeMarket).Msg("Started querying fee market base fee")
+		queryStart := time.Now()
+
+		baseFees, err := s.GetBaseFees(config)
+		if err != nil {
+			sublogger.Error().
+				Str("feemarket", config.FeeMarket).
+				Err(err).
+				Msg("Could not get fee market base fee")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying fee market base fee")
+
+		for _, baseFee := range baseFees {
+			// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+			if value, err := strconv.ParseFloat(baseFee.Amount.String(), 64); err != nil {
+				sublogger.Error().
+					Str("denom", baseFee.Denom).
+					Err(err).
+					Msg("Could not parse base fee")
+			} else {
+				metrics.baseFeeGauge.With(prometheus.Labels{
+					"denom": baseFee.Denom,
+				}).Set(value)
+			}
+		}
+	}()
+
+}
+
+// GetBaseFees returns the current base fee of the fee market module configured with --feemarket
+func (s *Service) GetBaseFees(config *ServiceConfig) (sdk.DecCoins, error) {
+	switch config.FeeMarket {
+	case FeeMarketSkip:
+		response, err := s.rawQuery(context.Background(), "/feemarket.feemarket.v1.Query/GasPrices", nil)
+		if err != nil {
+			return nil, err
+		}
+		// GasPricesResponse: repeated cosmos.base.v1beta1.DecCoin prices = 1
+		prices, err := rawBytesFields(response, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		var baseFees sdk.DecCoins
+		for _, price := range prices {
+			denom, err := rawBytesField(price, 1)
+			if err != nil {
+				return nil, err
+			}
+			amount, err := rawDecField(price, 2)
+			if err != nil {
+				return nil, err
+			}
+			baseFees = append(baseFees, sdk.DecCoin{Denom: string(denom), Amount: amount})
+		}
+		return baseFees, nil
+
+	case FeeMarketOsmosis:
+		response, err := s.rawQuery(context.Background(), "/osmosis.txfees.v1beta1.Query/GetEip1559BaseFee", nil)
+		if err != nil {
+			return nil, err
+		}
+		// QueryEipBaseFeeResponse: string base_fee = 1 (sdk.Dec)
+		baseFee, err := rawDecField(response, 1)
+		if err != nil {
+			return nil, err
+		}
+		return s.bondDenomFees(baseFee)
+
+	case FeeMarketEvmos:
+		response, err := s.rawQuery(context.Background(), "/ethermint.feemarket.v1.Query/BaseFee", nil)
+		if err != nil {
+			return nil, err
+		}
+		// QueryBaseFeeResponse: string base_fee = 1 (sdk.Int)
+		value, err := rawBytesField(response, 1)
+		if err != nil {
+			return nil, err
+		}
+		baseFee := sdk.ZeroInt()
+		if len(value) > 0 {
+			if err := baseFee.Unmarshal(value); err != nil {
+				return nil, err
+			}
+		}
+		return s.bondDenomFees(sdk.NewDecFromInt(baseFee))
+	}
+
+	return nil, fmt.Errorf("unknown fee market %q, must be one of %s, %s or %s", config.FeeMarket, FeeMarketSkip, FeeMarketOsmosis, FeeMarketEvmos)
+}
+
+// bondDenomFees labels a single base fee with the staking bond denom, as the fee markets only returning
+// one value don't tell which denom it's in
+func (s *Service) bondDenomFees(baseFee sdk.Dec) (sdk.DecCoins, error) {
+	params, err := s.GetStakingParams()
+	if err != nil {
+		return nil, err
+	}
+	return sdk.DecCoins{{Denom: params.BondDenom, Amount: baseFee}}, nil
+}
+func (s *Service) FeeMarketHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/feemarket")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	feeMarketMetrics := NewFeeMarketMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetFeeMarketMetrics(&wg, &sublogger, feeMarketMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/feemarket").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
@@ -0,0 +1,127 @@
+package exporter
+
+import (
+	"context"
+	"github.com/cosmos/cosmos-sdk/client/grpc/node"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type GasMetrics struct {
+	minGasPriceGauge *prometheus.GaugeVec
+}
+
+func NewGasMetrics(reg prometheus.Registerer, config *ServiceConfig) *GasMetrics {
+	m := &GasMetrics{
+		minGasPriceGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_min_gas_price",
+				Help:        "Minimum gas price accepted by the node, per gas unit",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"denom"},
+		),
+	}
+	reg.MustRegister(m.minGasPriceGauge)
+	return m
+}
+func GetGasMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *GasMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// on fee market chains the node's static setting is not what txs have to pay, the base fee is
+		if config.FeeMarket != "" {
+			baseFees, err := s.GetBaseFees(config)
+			if err != nil {
+				sublogger.Error().
+					Str("feemarket", config.FeeMarket).
+					Err(err).
+					Msg("Could not get fee market base fee")
+				return
+			}
+			setGasPrices(sublogger, metrics, baseFees)
+			return
+		}
+
+		minGasPrices := config.MinGasPrices
+		if minGasPrices == "" {
+			sublogger.Debug().Msg("Started querying node config")
+			queryStart := time.Now()
+
+			nodeClient := node.NewServiceClient(s.GrpcConn)
+			configRes, err := nodeClient.Config(
+				context.Background(),
+				&node.ConfigRequest{},
+			)
+			if err != nil {
+				sublogger.Error().
+					Err(err).
+					Msg("Could not get node config")
+				return
+			}
+
+			sublogger.Debug().
+				Float64("request-time", time.Since(queryStart).Seconds()).
+				Msg("Finished querying node config")
+			minGasPrices = configRes.MinimumGasPrice
+		}
+
+		prices, err := sdk.ParseDecCoins(minGasPrices)
+		if err != nil {
+			sublogger.Error().
+				Str("min-gas-prices", minGasPrices).
+				Err(err).
+				Msg("Could not parse minimum gas prices")
+			return
+		}
+
+		setGasPrices(sublogger, metrics, prices)
+	}()
+
+}
+func setGasPrices(sublogger *zerolog.Logger, metrics *GasMetrics, prices sdk.DecCoins) {
+	for _, price := range prices {
+		// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+		if value, err := strconv.ParseFloat(price.Amount.String(), 64); err != nil {
+			sublogger.Error().
+				Str("denom", price.Denom).
+				Err(err).
+				Msg("Could not parse minimum gas price")
+		} else {
+			metrics.minGasPriceGauge.With(prometheus.Labels{
+				"denom": price.Denom,
+			}).Set(value)
+		}
+	}
+}
+func (s *Service) GasHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/gas")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	gasMetrics := NewGasMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetGasMetrics(&wg, &sublogger, gasMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/gas").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
@@ -3,6 +3,7 @@ package exporter
 import (
 	"context"
 	tmservice "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	query "github.com/cosmos/cosmos-sdk/types/query"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
@@ -18,9 +19,7 @@ import (
 	"sync"
 	"time"
 	//minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
-	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type GeneralMetrics struct {
@@ -29,8 +28,11 @@ type GeneralMetrics struct {
 	communityPoolGauge       *prometheus.GaugeVec
 	supplyTotalGauge         *prometheus.GaugeVec
 	latestBlockHeight        prometheus.Gauge
+	secondsSinceLastBlock    prometheus.Gauge
 	syncing                  prometheus.Gauge
 	tokenPrice               prometheus.Gauge
+	tokenPriceUSD            *prometheus.GaugeVec
+	bondedTokensUSDGauge     prometheus.Gauge
 	govVotingPeriodProposals prometheus.Gauge
 	// GetNodeInfo
 	applicationVersion *prometheus.GaugeVec
@@ -76,6 +78,13 @@ func NewGeneralMetrics(reg prometheus.Registerer, config *ServiceConfig) *Genera
 				ConstLabels: config.ConstLabels,
 			},
 		),
+		secondsSinceLastBlock: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_chain_seconds_since_last_block",
+				Help:        "Seconds since the time of the latest block",
+				ConstLabels: config.ConstLabels,
+			},
+		),
 		syncing: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Name:        "cosmos_node_syncing",
@@ -90,6 +99,21 @@ func NewGeneralMetrics(reg prometheus.Registerer, config *ServiceConfig) *Genera
 				ConstLabels: config.ConstLabels,
 			},
 		),
+		tokenPriceUSD: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_token_price_usd",
+				Help:        "USD price of the token from --price-api-url",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"denom"},
+		),
+		bondedTokensUSDGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_general_bonded_tokens_usd",
+				Help:        "Bonded tokens valued in USD with the price from --price-api-url",
+				ConstLabels: config.ConstLabels,
+			},
+		),
 		govVotingPeriodProposals: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Name:        "cosmos_gov_voting_period_proposals",
@@ -124,10 +148,15 @@ func NewGeneralMetrics(reg prometheus.Registerer, config *ServiceConfig) *Genera
 	// registry.MustRegister(generalAnnualProvisions)
 
 	reg.MustRegister(m.latestBlockHeight)
+	reg.MustRegister(m.secondsSinceLastBlock)
 	reg.MustRegister(m.syncing)
 	if config.TokenPrice {
 		reg.MustRegister(m.tokenPrice)
 	}
+	if config.PriceAPIURL != "" {
+		reg.MustRegister(m.tokenPriceUSD)
+		reg.MustRegister(m.bondedTokensUSDGauge)
+	}
 	reg.MustRegister(m.govVotingPeriodProposals)
 	// nodeInfo
 	reg.MustRegister(m.applicationVersion)
@@ -194,6 +223,27 @@ func GetGeneralMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *G
 
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying chain status")
+
+		queryStart := time.Now()
+
+		cs, err := s.chainStatus()
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get chain status")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying chain status")
+
+		// climbs past the block time when the chain halts, without having to compare heights between scrapes
+		metrics.secondsSinceLastBlock.Set(time.Since(cs.LatestBlockTime()).Seconds())
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -253,6 +303,19 @@ func GetGeneralMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *G
 
 		metrics.bondedTokensGauge.Set(bondedTokens)
 		metrics.notBondedTokensGauge.Set(notBondedTokens)
+
+		if config.PriceAPIURL != "" {
+			price, err := s.GetPriceUSD()
+			if err != nil {
+				sublogger.Error().Err(err).Msg("Could not get token price")
+				return
+			}
+
+			metrics.tokenPriceUSD.With(prometheus.Labels{
+				"denom": config.Denom,
+			}).Set(price)
+			metrics.bondedTokensUSDGauge.Set(bondedTokens / config.DenomCoefficient * price)
+		}
 		//fmt.Println("response: ", response.Pool.BondedTokens)
 		//generalBondedTokensGauge.Set(float64(response.Pool.BondedTokens.Int64()))
 		//generalNotBondedTokensGauge.Set(float64(response.Pool.NotBondedTokens.Int64()))
@@ -336,42 +399,40 @@ func GetGeneralMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *G
 		queryStart := time.Now()
 
 		bankClient := banktypes.NewQueryClient(s.GrpcConn)
-		response, err := bankClient.TotalSupply(
-			context.Background(),
-			&banktypes.QueryTotalSupplyRequest{},
-		)
-		for {
-			if err != nil {
-				sublogger.Error().Err(err).Msg("Could not get bank total supply")
-				return
-			}
-
-			sublogger.Debug().
-				Float64("request-time", time.Since(queryStart).Seconds()).
-				Msg("Finished querying bank total supply")
-
-			for _, coin := range response.Supply {
-				if value, err := strconv.ParseFloat(coin.Amount.String(), 64); err != nil {
-					sublogger.Error().
-						Err(err).
-						Msg("Could not get total supply")
-				} else {
-					metrics.supplyTotalGauge.With(prometheus.Labels{
-						"denom": coin.GetDenom(),
-					}).Set(value)
-				}
-			}
-			if response.Pagination.NextKey == nil {
-				break
-			}
-			response, err = bankClient.TotalSupply(
+		supply, err := Paginate(func(key []byte) ([]sdk.Coin, []byte, error) {
+			response, err := bankClient.TotalSupply(
 				context.Background(),
 				&banktypes.QueryTotalSupplyRequest{
 					Pagination: &query.PageRequest{
-						Key: response.Pagination.NextKey,
+						Key:   key,
+						Limit: config.Limit,
 					},
 				},
 			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return response.Supply, response.Pagination.GetNextKey(), nil
+		})
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get bank total supply")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying bank total supply")
+
+		for _, coin := range supply {
+			if value, err := strconv.ParseFloat(coin.Amount.String(), 64); err != nil {
+				sublogger.Error().
+					Err(err).
+					Msg("Could not get total supply")
+			} else {
+				metrics.supplyTotalGauge.With(prometheus.Labels{
+					"denom": coin.GetDenom(),
+				}).Set(value)
+			}
 		}
 	}()
 	/*
@@ -444,17 +505,14 @@ func GetGeneralMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *G
 
 			sublogger.Debug().Msg("Started querying global gov V1 params")
 
-			govClient := govtypeV1.NewQueryClient(s.GrpcConn)
-			proposals, err := govClient.Proposals(context.Background(), &govtypeV1.QueryProposalsRequest{
-				ProposalStatus: govtypeV1.StatusVotingPeriod,
-			})
+			proposals, err := s.queryProposalsV1(govtypeV1.StatusVotingPeriod)
 			if err != nil {
 				sublogger.Error().
 					Err(err).
 					Msg("Could not get active proposals")
+				return
 			}
-			proposalsCount := len(proposals.GetProposals())
-			metrics.govVotingPeriodProposals.Set(float64(proposalsCount))
+			metrics.govVotingPeriodProposals.Set(float64(len(proposals)))
 		}()
 	} else {
 		wg.Add(1)
@@ -463,31 +521,28 @@ func GetGeneralMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *G
 
 			sublogger.Debug().Msg("Started querying global gov v1beta1 params")
 
-			govClient := govtypes.NewQueryClient(s.GrpcConn)
-			proposals, err := govClient.Proposals(context.Background(), &govtypes.QueryProposalsRequest{
-				ProposalStatus: govtypes.StatusVotingPeriod,
-			})
+			proposals, err := s.queryProposals(govtypes.StatusVotingPeriod)
 			if err != nil {
 				sublogger.Error().
 					Err(err).
 					Msg("Could not get active proposals")
+				return
 			}
-
-			proposalsCount := len(proposals.GetProposals())
-			metrics.govVotingPeriodProposals.Set(float64(proposalsCount))
+			metrics.govVotingPeriodProposals.Set(float64(len(proposals)))
 		}()
 	}
 
 }
 
 func (s *Service) GeneralHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/general")
 	requestStart := time.Now()
 
 	sublogger := s.Log.With().
-		Str("request-id", uuid.New().String()).
+		Str("request-id", RequestID(r)).
 		Logger()
 
-	registry := prometheus.NewRegistry()
+	registry := s.Config.NewRegistry()
 	generalMetrics := NewGeneralMetrics(registry, s.Config)
 
 	var wg sync.WaitGroup
@@ -496,8 +551,7 @@ func (s *Service) GeneralHandler(w http.ResponseWriter, r *http.Request) {
 
 	wg.Wait()
 
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	h.ServeHTTP(w, r)
+	s.ServeMetrics(w, r, registry)
 	sublogger.Info().
 		Str("method", "GET").
 		Str("endpoint", "/metrics/general").
@@ -0,0 +1,41 @@
+package exporter_test
+
+import (
+	"main/pkg/exporter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestGeneralHandlerVotingPeriodProposalsPages(t *testing.T) {
+	s := newTestService(t, func(server *grpc.Server) {
+		govtypes.RegisterQueryServer(server, &pagedGovServer{t: t})
+	}, &exporter.ServiceConfig{Limit: 1, DenomCoefficient: 1, TendermintRPC: "http://127.0.0.1:1"})
+
+	recorder := httptest.NewRecorder()
+	s.GeneralHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/general", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "\ncosmos_gov_voting_period_proposals 3\n")
+}
+
+func TestGeneralHandlerTotalSupplyLimit(t *testing.T) {
+	bankServer := &pagedBankServer{}
+	s := newTestService(t, func(server *grpc.Server) {
+		banktypes.RegisterQueryServer(server, bankServer)
+	}, &exporter.ServiceConfig{Limit: 1, DenomCoefficient: 1, TendermintRPC: "http://127.0.0.1:1"})
+
+	recorder := httptest.NewRecorder()
+	s.GeneralHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/general", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `cosmos_general_supply_total{denom="uatom"} 1e+06`+"\n")
+	require.Contains(t, recorder.Body.String(), `cosmos_general_supply_total{denom="uosmo"} 2e+06`+"\n")
+	// --limit is the page size, like for the other list queries
+	require.Equal(t, []uint64{1, 1}, bankServer.supplyLimits)
+}
@@ -0,0 +1,260 @@
+package exporter
+
+import (
+	"context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/cosmos-sdk/x/group"
+	"github.com/rs/zerolog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type GroupMetrics struct {
+	memberWeightGauge  *prometheus.GaugeVec
+	totalWeightGauge   *prometheus.GaugeVec
+	policyBalanceGauge *prometheus.GaugeVec
+}
+
+func NewGroupMetrics(reg prometheus.Registerer, config *ServiceConfig) *GroupMetrics {
+	m := &GroupMetrics{
+		memberWeightGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_group_member_weight",
+				Help:        "Voting weight of the group member",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"group_id", "address"},
+		),
+		totalWeightGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_group_total_weight",
+				Help:        "Sum of the voting weights of the group members",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"group_id"},
+		),
+		policyBalanceGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_group_policy_balance",
+				Help:        "Balance of the group policy account",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"group_id", "address", "denom"},
+		),
+	}
+	reg.MustRegister(m.memberWeightGauge)
+	reg.MustRegister(m.totalWeightGauge)
+	reg.MustRegister(m.policyBalanceGauge)
+	return m
+}
+func GetGroupMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *GroupMetrics, s *Service, config *ServiceConfig) {
+	groupID := strconv.FormatUint(config.GroupID, 10)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Str("group_id", groupID).Msg("Started querying group info")
+		queryStart := time.Now()
+
+		groupClient := group.NewQueryClient(s.GrpcConn)
+		groupInfoResponse, err := groupClient.GroupInfo(
+			context.Background(),
+			&group.QueryGroupInfoRequest{GroupId: config.GroupID},
+		)
+		if err != nil {
+			sublogger.Error().
+				Str("group_id", groupID).
+				Err(err).
+				Msg("Could not get group info")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying group info")
+
+		// weights are decimal strings, not sdk decs
+		if value, err := strconv.ParseFloat(groupInfoResponse.Info.TotalWeight, 64); err != nil {
+			sublogger.Error().
+				Str("group_id", groupID).
+				Err(err).
+				Msg("Could not parse group total weight")
+		} else {
+			metrics.totalWeightGauge.With(prometheus.Labels{
+				"group_id": groupID,
+			}).Set(value)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Str("group_id", groupID).Msg("Started querying group members")
+		queryStart := time.Now()
+
+		groupClient := group.NewQueryClient(s.GrpcConn)
+
+		members, err := Paginate(func(key []byte) ([]*group.GroupMember, []byte, error) {
+			membersResponse, err := groupClient.GroupMembers(
+				context.Background(),
+				&group.QueryGroupMembersRequest{
+					GroupId: config.GroupID,
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return membersResponse.Members, membersResponse.Pagination.GetNextKey(), nil
+		})
+		if err != nil {
+			sublogger.Error().
+				Str("group_id", groupID).
+				Err(err).
+				Msg("Could not get group members")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int("membersLength", len(members)).
+			Msg("Finished querying group members")
+
+		for _, member := range members {
+			if member.Member == nil {
+				continue
+			}
+
+			if value, err := strconv.ParseFloat(member.Member.Weight, 64); err != nil {
+				sublogger.Error().
+					Str("address", member.Member.Address).
+					Err(err).
+					Msg("Could not parse group member weight")
+			} else {
+				metrics.memberWeightGauge.With(prometheus.Labels{
+					"group_id": groupID,
+					"address":  member.Member.Address,
+				}).Set(value)
+			}
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Str("group_id", groupID).Msg("Started querying group policies")
+		queryStart := time.Now()
+
+		groupClient := group.NewQueryClient(s.GrpcConn)
+
+		policies, err := Paginate(func(key []byte) ([]*group.GroupPolicyInfo, []byte, error) {
+			policiesResponse, err := groupClient.GroupPoliciesByGroup(
+				context.Background(),
+				&group.QueryGroupPoliciesByGroupRequest{
+					GroupId: config.GroupID,
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return policiesResponse.GroupPolicies, policiesResponse.Pagination.GetNextKey(), nil
+		})
+		if err != nil {
+			sublogger.Error().
+				Str("group_id", groupID).
+				Err(err).
+				Msg("Could not get group policies")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int("policiesLength", len(policies)).
+			Msg("Finished querying group policies")
+
+		for _, policy := range policies {
+			wg.Add(1)
+			go func(address string) {
+				defer wg.Done()
+
+				bankClient := banktypes.NewQueryClient(s.GrpcConn)
+				balances, err := Paginate(func(key []byte) ([]sdk.Coin, []byte, error) {
+					bankRes, err := bankClient.AllBalances(
+						context.Background(),
+						&banktypes.QueryAllBalancesRequest{
+							Address: address,
+							Pagination: &querytypes.PageRequest{
+								Key:   key,
+								Limit: config.Limit,
+							},
+						},
+					)
+					if err != nil {
+						return nil, nil, err
+					}
+					return bankRes.Balances, bankRes.Pagination.GetNextKey(), nil
+				})
+				if err != nil {
+					sublogger.Error().
+						Str("address", address).
+						Err(err).
+						Msg("Could not get group policy balance")
+					return
+				}
+
+				for _, balance := range balances {
+					// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+					if value, err := strconv.ParseFloat(balance.Amount.String(), 64); err != nil {
+						sublogger.Error().
+							Str("address", address).
+							Err(err).
+							Msg("Could not parse group policy balance")
+					} else {
+						metrics.policyBalanceGauge.With(prometheus.Labels{
+							"group_id": groupID,
+							"address":  address,
+							"denom":    balance.Denom,
+						}).Set(value / config.DenomCoefficient)
+					}
+				}
+			}(policy.Address)
+		}
+	}()
+
+}
+func (s *Service) GroupHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/group")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	groupMetrics := NewGroupMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetGroupMetrics(&wg, &sublogger, groupMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/group").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
@@ -0,0 +1,86 @@
+package exporter_test
+
+import (
+	"context"
+	"main/pkg/exporter"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/cosmos-sdk/x/group"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// pagedGroupServer serves one group policy per page
+type pagedGroupServer struct {
+	group.UnimplementedQueryServer
+}
+
+func (pagedGroupServer) GroupPoliciesByGroup(_ context.Context, request *group.QueryGroupPoliciesByGroupRequest) (*group.QueryGroupPoliciesByGroupResponse, error) {
+	if len(request.Pagination.GetKey()) == 0 {
+		return &group.QueryGroupPoliciesByGroupResponse{
+			GroupPolicies: []*group.GroupPolicyInfo{{Address: "first-policy", GroupId: request.GroupId}},
+			Pagination:    &querytypes.PageResponse{NextKey: []byte("second")},
+		}, nil
+	}
+	return &group.QueryGroupPoliciesByGroupResponse{
+		GroupPolicies: []*group.GroupPolicyInfo{{Address: "second-policy", GroupId: request.GroupId}},
+	}, nil
+}
+
+// pagedBankServer serves one balance and one supply per page, recording the page size of the supply queries
+type pagedBankServer struct {
+	banktypes.UnimplementedQueryServer
+
+	mutex        sync.Mutex
+	supplyLimits []uint64
+}
+
+func (server *pagedBankServer) TotalSupply(_ context.Context, request *banktypes.QueryTotalSupplyRequest) (*banktypes.QueryTotalSupplyResponse, error) {
+	server.mutex.Lock()
+	server.supplyLimits = append(server.supplyLimits, request.Pagination.GetLimit())
+	server.mutex.Unlock()
+
+	if len(request.Pagination.GetKey()) == 0 {
+		return &banktypes.QueryTotalSupplyResponse{
+			Supply:     sdk.NewCoins(sdk.NewInt64Coin("uatom", 1000000)),
+			Pagination: &querytypes.PageResponse{NextKey: []byte("second")},
+		}, nil
+	}
+	return &banktypes.QueryTotalSupplyResponse{
+		Supply: sdk.NewCoins(sdk.NewInt64Coin("uosmo", 2000000)),
+	}, nil
+}
+
+func (*pagedBankServer) AllBalances(_ context.Context, request *banktypes.QueryAllBalancesRequest) (*banktypes.QueryAllBalancesResponse, error) {
+	if len(request.Pagination.GetKey()) == 0 {
+		return &banktypes.QueryAllBalancesResponse{
+			Balances:   sdk.NewCoins(sdk.NewInt64Coin("uatom", 1000000)),
+			Pagination: &querytypes.PageResponse{NextKey: []byte("second")},
+		}, nil
+	}
+	return &banktypes.QueryAllBalancesResponse{
+		Balances: sdk.NewCoins(sdk.NewInt64Coin("uosmo", 2000000)),
+	}, nil
+}
+
+func TestGroupHandlerPages(t *testing.T) {
+	s := newTestService(t, func(server *grpc.Server) {
+		group.RegisterQueryServer(server, &pagedGroupServer{})
+		banktypes.RegisterQueryServer(server, &pagedBankServer{})
+	}, &exporter.ServiceConfig{Limit: 1, DenomCoefficient: 1000000, GroupID: 1})
+
+	recorder := httptest.NewRecorder()
+	s.GroupHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/group", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	for _, policy := range []string{"first-policy", "second-policy"} {
+		require.Contains(t, recorder.Body.String(), `cosmos_group_policy_balance{address="`+policy+`",denom="uatom",group_id="1"} 1`+"\n")
+		require.Contains(t, recorder.Body.String(), `cosmos_group_policy_balance{address="`+policy+`",denom="uosmo",group_id="1"} 2`+"\n")
+	}
+}
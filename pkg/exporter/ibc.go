@@ -0,0 +1,266 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/types"
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	channeltypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type IBCMetrics struct {
+	escrowBalanceGauge *prometheus.GaugeVec
+	voucherSupplyGauge *prometheus.GaugeVec
+}
+
+func NewIBCMetrics(reg prometheus.Registerer, config *ServiceConfig) *IBCMetrics {
+	m := &IBCMetrics{
+		escrowBalanceGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_ibc_escrow_balance",
+				Help:        "Balance of the IBC transfer escrow account of the channel, in base units",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"channel_id", "denom"},
+		),
+		voucherSupplyGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_ibc_voucher_supply",
+				Help:        "Total supply of the IBC voucher denom, in base units",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"denom", "base_denom", "channel"},
+		),
+	}
+	reg.MustRegister(m.escrowBalanceGauge)
+	reg.MustRegister(m.voucherSupplyGauge)
+	return m
+}
+func GetIBCMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *IBCMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying IBC channels")
+		queryStart := time.Now()
+
+		channelClient := channeltypes.NewQueryClient(s.GrpcConn)
+
+		channels, err := Paginate(func(key []byte) ([]*channeltypes.IdentifiedChannel, []byte, error) {
+			channelsResponse, err := channelClient.Channels(
+				context.Background(),
+				&channeltypes.QueryChannelsRequest{
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return channelsResponse.Channels, channelsResponse.Pagination.GetNextKey(), nil
+		})
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get IBC channels")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int("channelsLength", len(channels)).
+			Msg("Finished querying IBC channels")
+
+		for _, channel := range channels {
+			if channel.PortId != transfertypes.PortID {
+				continue
+			}
+
+			wg.Add(1)
+			go func(channel *channeltypes.IdentifiedChannel) {
+				defer wg.Done()
+
+				escrowAddress := transfertypes.GetEscrowAddress(channel.PortId, channel.ChannelId)
+
+				bankClient := banktypes.NewQueryClient(s.GrpcConn)
+				balances, err := Paginate(func(key []byte) ([]types.Coin, []byte, error) {
+					bankRes, err := bankClient.AllBalances(
+						context.Background(),
+						&banktypes.QueryAllBalancesRequest{
+							Address: escrowAddress.String(),
+							Pagination: &querytypes.PageRequest{
+								Key:   key,
+								Limit: config.Limit,
+							},
+						},
+					)
+					if err != nil {
+						return nil, nil, err
+					}
+					return bankRes.Balances, bankRes.Pagination.GetNextKey(), nil
+				})
+				if err != nil {
+					sublogger.Error().
+						Str("channel_id", channel.ChannelId).
+						Str("address", escrowAddress.String()).
+						Err(err).
+						Msg("Could not get escrow balance")
+					return
+				}
+
+				for _, balance := range balances {
+					// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+					if value, err := strconv.ParseFloat(balance.Amount.String(), 64); err != nil {
+						sublogger.Error().
+							Str("channel_id", channel.ChannelId).
+							Err(err).
+							Msg("Could not parse escrow balance")
+					} else {
+						metrics.escrowBalanceGauge.With(prometheus.Labels{
+							"channel_id": channel.ChannelId,
+							"denom":      balance.Denom,
+						}).Set(value)
+					}
+				}
+			}(channel)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying IBC voucher supply")
+		queryStart := time.Now()
+
+		bankClient := banktypes.NewQueryClient(s.GrpcConn)
+
+		vouchers, err := Paginate(func(key []byte) ([]types.Coin, []byte, error) {
+			response, err := bankClient.TotalSupply(
+				context.Background(),
+				&banktypes.QueryTotalSupplyRequest{
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			var pageVouchers []types.Coin
+			for _, coin := range response.Supply {
+				if strings.HasPrefix(coin.Denom, transfertypes.DenomPrefix+"/") {
+					pageVouchers = append(pageVouchers, coin)
+				}
+			}
+			return pageVouchers, response.Pagination.GetNextKey(), nil
+		})
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get bank total supply")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int("vouchersLength", len(vouchers)).
+			Msg("Finished querying IBC voucher supply")
+
+		for _, voucher := range vouchers {
+			trace, err := s.GetDenomTrace(voucher.Denom)
+			if err != nil {
+				sublogger.Error().
+					Str("denom", voucher.Denom).
+					Err(err).
+					Msg("Could not get denom trace")
+				continue
+			}
+
+			// the first hop of the path is the channel the voucher came in through on this chain
+			channel := ""
+			if hops := strings.Split(trace.Path, "/"); len(hops) >= 2 {
+				channel = hops[1]
+			}
+
+			// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+			if value, err := strconv.ParseFloat(voucher.Amount.String(), 64); err != nil {
+				sublogger.Error().
+					Str("denom", voucher.Denom).
+					Err(err).
+					Msg("Could not parse voucher supply")
+			} else {
+				metrics.voucherSupplyGauge.With(prometheus.Labels{
+					"denom":      voucher.Denom,
+					"base_denom": trace.BaseDenom,
+					"channel":    channel,
+				}).Set(value)
+			}
+		}
+	}()
+
+}
+
+// GetDenomTrace resolves an ibc/HASH denom into its trace, caching the result as traces never change
+func (s *Service) GetDenomTrace(denom string) (transfertypes.DenomTrace, error) {
+	s.denomTracesMutex.Lock()
+	trace, ok := s.denomTraces[denom]
+	s.denomTracesMutex.Unlock()
+	if ok {
+		return trace, nil
+	}
+
+	transferClient := transfertypes.NewQueryClient(s.GrpcConn)
+	response, err := transferClient.DenomTrace(
+		context.Background(),
+		// older ibc-go versions only accept the hash, without the ibc/ prefix
+		&transfertypes.QueryDenomTraceRequest{Hash: strings.TrimPrefix(denom, transfertypes.DenomPrefix+"/")},
+	)
+	if err != nil {
+		return transfertypes.DenomTrace{}, err
+	}
+	if response.DenomTrace == nil {
+		return transfertypes.DenomTrace{}, fmt.Errorf("no denom trace found for %s", denom)
+	}
+
+	s.denomTracesMutex.Lock()
+	if s.denomTraces == nil {
+		s.denomTraces = make(map[string]transfertypes.DenomTrace)
+	}
+	s.denomTraces[denom] = *response.DenomTrace
+	s.denomTracesMutex.Unlock()
+
+	return *response.DenomTrace, nil
+}
+func (s *Service) IBCHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/ibc")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	ibcMetrics := NewIBCMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetIBCMetrics(&wg, &sublogger, ibcMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/ibc").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
@@ -0,0 +1,42 @@
+package exporter_test
+
+import (
+	"context"
+	"main/pkg/exporter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	channeltypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type transferChannelServer struct {
+	channeltypes.UnimplementedQueryServer
+}
+
+func (transferChannelServer) Channels(context.Context, *channeltypes.QueryChannelsRequest) (*channeltypes.QueryChannelsResponse, error) {
+	return &channeltypes.QueryChannelsResponse{Channels: []*channeltypes.IdentifiedChannel{
+		{PortId: transfertypes.PortID, ChannelId: "channel-0"},
+	}}, nil
+}
+
+func TestIBCHandlerEscrowBalancePages(t *testing.T) {
+	bankServer := &pagedBankServer{}
+	s := newTestService(t, func(server *grpc.Server) {
+		channeltypes.RegisterQueryServer(server, &transferChannelServer{})
+		banktypes.RegisterQueryServer(server, bankServer)
+	}, &exporter.ServiceConfig{Limit: 1, DenomCoefficient: 1})
+
+	recorder := httptest.NewRecorder()
+	s.IBCHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/ibc", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `cosmos_ibc_escrow_balance{channel_id="channel-0",denom="uatom"} 1e+06`+"\n")
+	require.Contains(t, recorder.Body.String(), `cosmos_ibc_escrow_balance{channel_id="channel-0",denom="uosmo"} 2e+06`+"\n")
+	// the voucher supply is paged with --limit too
+	require.Equal(t, []uint64{1, 1}, bankServer.supplyLimits)
+}
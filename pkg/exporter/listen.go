@@ -0,0 +1,43 @@
+package exporter
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// unixSocketPrefix makes --listen-address a unix socket path, like unix:/run/cosmos-exporter.sock
+const unixSocketPrefix = "unix:"
+
+// Listen returns the listener of --listen-address, a TCP address or a unix socket to not expose the metrics
+// on the network of shared hosts. The socket gets the --listen-socket-mode permissions.
+func (config *ServiceConfig) Listen() (net.Listener, error) {
+	if !strings.HasPrefix(config.ListenAddress, unixSocketPrefix) {
+		return net.Listen("tcp", config.ListenAddress)
+	}
+
+	path := strings.TrimPrefix(config.ListenAddress, unixSocketPrefix)
+	mode, err := strconv.ParseUint(config.ListenSocketMode, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --listen-socket-mode %q, expected an octal mode like 0660: %w", config.ListenSocketMode, err)
+	}
+
+	// the socket of a previous run which didn't shut down cleanly, anything else is left alone
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, os.FileMode(mode)); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
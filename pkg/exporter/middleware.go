@@ -0,0 +1,103 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/google/uuid"
+)
+
+type requestIDKey struct{}
+
+// RequestID returns the id the request was given by RecoverHandler, so the handler logs and the panic logs can be matched
+func RequestID(r *http.Request) string {
+	if requestID, ok := r.Context().Value(requestIDKey{}).(string); ok {
+		return requestID
+	}
+	return uuid.New().String()
+}
+
+// RecoverHandler gives every request an id and turns panics in the handlers into a logged 500,
+// instead of the connection being dropped without anything useful in the logs.
+// panics in the goroutines spawned by the handlers can't be recovered here and still crash the exporter
+func (s *Service) RecoverHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := uuid.New().String()
+		r = r.WithContext(context.WithValue(r.Context(), requestIDKey{}, requestID))
+
+		defer func() {
+			if err := recover(); err != nil {
+				s.Log.Error().
+					Str("request-id", requestID).
+					Str("endpoint", r.URL.Path).
+					Err(fmt.Errorf("%v", err)).
+					Str("stack", string(debug.Stack())).
+					Msg("Handler panicked")
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// recordedResponse is a response written by a handler, to be replayed to all the requests coalesced with it
+type recordedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recordedResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *recordedResponse) Write(body []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(body)
+}
+
+func (r *recordedResponse) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+// CoalesceHandler makes the concurrent identical scrapes, like the ones of HA Prometheus replicas, share a single
+// run of the handler and its queries to the node instead of doing all the work twice. The scrapes are identical if
+// they have the same path, query and exposition format, they're never cached past the end of the shared run.
+func (s *Service) CoalesceHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := r.URL.Path + "?" + r.URL.RawQuery + "\n" + r.Header.Get("Accept") + "\n" + r.Header.Get("Accept-Encoding")
+		response, _, shared := s.scrapes.Do(key, func() (interface{}, error) {
+			response := &recordedResponse{header: make(http.Header)}
+			next.ServeHTTP(response, r)
+			return response, nil
+		})
+		if shared {
+			s.Log.Debug().
+				Str("request-id", RequestID(r)).
+				Str("endpoint", r.URL.Path).
+				Msg("Shared the response of a concurrent scrape")
+		}
+
+		recorded := response.(*recordedResponse)
+		for name, values := range recorded.header {
+			w.Header()[name] = values
+		}
+		if recorded.status != 0 {
+			w.WriteHeader(recorded.status)
+		}
+		_, _ = w.Write(recorded.body.Bytes())
+	})
+}
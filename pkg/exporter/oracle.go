@@ -0,0 +1,143 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// oracle modules which can be passed to --oracle-module
+const (
+	OracleModuleKujira = "kujira" // kujira x/oracle
+	OracleModuleTerra  = "terra"  // terra classic x/oracle, which most oracle modules are forked from
+	OracleModuleSei    = "sei"    // sei x/oracle
+)
+
+type OracleMetrics struct {
+	missCounterGauge *prometheus.GaugeVec
+}
+
+func NewOracleMetrics(reg prometheus.Registerer, config *ServiceConfig) *OracleMetrics {
+	m := &OracleMetrics{
+		missCounterGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_oracle_miss_counter",
+				Help:        "Price feed votes missed by the Cosmos-based blockchain validator in the current oracle slash window",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+	}
+	reg.MustRegister(m.missCounterGauge)
+	return m
+}
+func GetOracleMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *OracleMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying validators")
+		queryStart := time.Now()
+
+		validators, err := s.GetValidators()
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get validators")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying validators")
+
+		// only the bonded validators have to submit price feeds
+		for _, validator := range validators {
+			if !validator.IsBonded() {
+				continue
+			}
+
+			wg.Add(1)
+			go func(validator stakingtypes.Validator) {
+				defer wg.Done()
+
+				missCounter, err := s.GetOracleMissCounter(config, validator.OperatorAddress)
+				if err != nil {
+					sublogger.Error().
+						Str("address", validator.OperatorAddress).
+						Err(err).
+						Msg("Could not get oracle miss counter")
+					return
+				}
+
+				metrics.missCounterGauge.With(prometheus.Labels{
+					"address": validator.OperatorAddress,
+					"moniker": validator.Description.Moniker,
+				}).Set(float64(missCounter))
+			}(validator)
+		}
+	}()
+
+}
+
+// GetOracleMissCounter queries the oracle module set with --oracle-module for the missed votes of the validator.
+// The requests only have the validator_addr = 1 field, so they're encoded by hand instead of importing every chain.
+func (s *Service) GetOracleMissCounter(config *ServiceConfig, validatorAddress string) (uint64, error) {
+	request := protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), validatorAddress)
+
+	switch config.OracleModule {
+	case OracleModuleKujira, OracleModuleTerra:
+		method := "/kujira.oracle.Query/MissCounter"
+		if config.OracleModule == OracleModuleTerra {
+			method = "/terra.oracle.v1beta1.Query/MissCounter"
+		}
+		response, err := s.rawQuery(context.Background(), method, request)
+		if err != nil {
+			return 0, err
+		}
+		// QueryMissCounterResponse { uint64 miss_counter = 1; }
+		return rawVarintField(response, 1)
+	case OracleModuleSei:
+		response, err := s.rawQuery(context.Background(), "/seiprotocol.seichain.oracle.Query/VotePenaltyCounter", request)
+		if err != nil {
+			return 0, err
+		}
+		// QueryVotePenaltyCounterResponse { VotePenaltyCounter vote_penalty_counter = 1; },
+		// VotePenaltyCounter { uint64 miss_count = 1; uint64 abstain_count = 2; uint64 success_count = 3; }
+		counter, err := rawBytesField(response, 1)
+		if err != nil {
+			return 0, err
+		}
+		return rawVarintField(counter, 1)
+	default:
+		return 0, fmt.Errorf("unknown oracle module %q", config.OracleModule)
+	}
+}
+func (s *Service) OracleHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/oracle")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	oracleMetrics := NewOracleMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetOracleMetrics(&wg, &sublogger, oracleMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/oracle").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
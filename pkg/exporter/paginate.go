@@ -0,0 +1,30 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Paginate goes through all the pages of a query, calling fetch with the key of every page, nil for the first one,
+// until it returns an empty next key, and returns the items of all the pages. The queries only returning their first
+// page when the pagination is forgotten is how the validators and signing infos used to be truncated on large chains.
+func Paginate[T any](fetch func(key []byte) ([]T, []byte, error)) ([]T, error) {
+	var items []T
+	var key []byte
+	for {
+		page, nextKey, err := fetch(key)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+
+		if len(nextKey) == 0 {
+			return items, nil
+		}
+		// a node sending the same key again would keep us going forever
+		if bytes.Equal(nextKey, key) {
+			return nil, fmt.Errorf("pagination is stuck on the next key %X", nextKey)
+		}
+		key = nextKey
+	}
+}
@@ -0,0 +1,59 @@
+package exporter_test
+
+import (
+	"errors"
+	"main/pkg/exporter"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginateMultiplePages(t *testing.T) {
+	pages := map[string]struct {
+		items   []int
+		nextKey []byte
+	}{
+		"":       {items: []int{1, 2}, nextKey: []byte("second")},
+		"second": {items: []int{3, 4}, nextKey: []byte("third")},
+		"third":  {items: []int{5}},
+	}
+
+	var keys []string
+	items, err := exporter.Paginate(func(key []byte) ([]int, []byte, error) {
+		keys = append(keys, string(key))
+		page := pages[string(key)]
+		return page.items, page.nextKey, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, items)
+	require.Equal(t, []string{"", "second", "third"}, keys)
+}
+
+func TestPaginateSinglePage(t *testing.T) {
+	calls := 0
+	items, err := exporter.Paginate(func(key []byte) ([]int, []byte, error) {
+		calls++
+		require.Nil(t, key)
+		return []int{1, 2, 3}, nil, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3}, items)
+	require.Equal(t, 1, calls)
+}
+
+func TestPaginateError(t *testing.T) {
+	_, err := exporter.Paginate(func(key []byte) ([]int, []byte, error) {
+		if key == nil {
+			return []int{1}, []byte("second"), nil
+		}
+		return nil, nil, errors.New("node is down")
+	})
+	require.EqualError(t, err, "node is down")
+}
+
+func TestPaginateStuckKey(t *testing.T) {
+	_, err := exporter.Paginate(func(key []byte) ([]int, []byte, error) {
+		return []int{1}, []byte("same"), nil
+	})
+	require.Error(t, err)
+}
@@ -5,16 +5,13 @@ import (
 	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
 	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
-	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	"github.com/rs/zerolog"
 	"net/http"
 	"strconv"
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type ParamsMetrics struct {
@@ -33,6 +30,10 @@ type ParamsMetrics struct {
 	baseProposerRewardGauge   prometheus.Gauge
 	bonusProposerRewardGauge  prometheus.Gauge
 	communityTaxGauge         prometheus.Gauge
+
+	distributionCommunityTaxGauge        prometheus.Gauge
+	distributionBaseProposerRewardGauge  prometheus.Gauge
+	distributionBonusProposerRewardGauge prometheus.Gauge
 }
 
 func NewParamsMetrics(reg prometheus.Registerer, config *ServiceConfig) *ParamsMetrics {
@@ -142,6 +143,27 @@ func NewParamsMetrics(reg prometheus.Registerer, config *ServiceConfig) *ParamsM
 				ConstLabels: config.ConstLabels,
 			},
 		),
+		distributionCommunityTaxGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_distribution_community_tax",
+				Help:        "Share of the block rewards going to the community pool",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		distributionBaseProposerRewardGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_distribution_base_proposer_reward",
+				Help:        "Share of the block fees given to the block proposer",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		distributionBonusProposerRewardGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_distribution_bonus_proposer_reward",
+				Help:        "Maximum extra share of the block fees given to the block proposer for including precommits",
+				ConstLabels: config.ConstLabels,
+			},
+		),
 	}
 
 	reg.MustRegister(m.maxValidatorsGauge)
@@ -162,6 +184,9 @@ func NewParamsMetrics(reg prometheus.Registerer, config *ServiceConfig) *ParamsM
 	reg.MustRegister(m.baseProposerRewardGauge)
 	reg.MustRegister(m.bonusProposerRewardGauge)
 	reg.MustRegister(m.communityTaxGauge)
+	reg.MustRegister(m.distributionCommunityTaxGauge)
+	reg.MustRegister(m.distributionBaseProposerRewardGauge)
+	reg.MustRegister(m.distributionBonusProposerRewardGauge)
 
 	return m
 }
@@ -172,11 +197,7 @@ func GetParamsMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *Pa
 		sublogger.Debug().Msg("Started querying global staking params")
 		queryStart := time.Now()
 
-		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
-		paramsResponse, err := stakingClient.Params(
-			context.Background(),
-			&stakingtypes.QueryParamsRequest{},
-		)
+		params, err := s.GetStakingParams()
 		if err != nil {
 			sublogger.Error().
 				Err(err).
@@ -188,8 +209,8 @@ func GetParamsMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *Pa
 			Float64("request-time", time.Since(queryStart).Seconds()).
 			Msg("Finished querying global staking params")
 
-		metrics.maxValidatorsGauge.Set(float64(paramsResponse.Params.MaxValidators))
-		metrics.unbondingTimeGauge.Set(paramsResponse.Params.UnbondingTime.Seconds())
+		metrics.maxValidatorsGauge.Set(float64(params.MaxValidators))
+		metrics.unbondingTimeGauge.Set(params.UnbondingTime.Seconds())
 	}()
 	wg.Add(1)
 
@@ -324,42 +345,45 @@ func GetParamsMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *Pa
 			Float64("request-time", time.Since(queryStart).Seconds()).
 			Msg("Finished querying global distribution params")
 
-		// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
-		if value, err := strconv.ParseFloat(paramsResponse.Params.BaseProposerReward.String(), 64); err != nil {
+		if value, err := DecToFloat64(paramsResponse.Params.BaseProposerReward); err != nil {
 			sublogger.Error().
 				Err(err).
 				Msg("Could not parse base proposer reward")
 		} else {
 			metrics.baseProposerRewardGauge.Set(value)
+			metrics.distributionBaseProposerRewardGauge.Set(value)
 		}
 
-		if value, err := strconv.ParseFloat(paramsResponse.Params.BonusProposerReward.String(), 64); err != nil {
+		if value, err := DecToFloat64(paramsResponse.Params.BonusProposerReward); err != nil {
 			sublogger.Error().
 				Err(err).
 				Msg("Could not parse bonus proposer reward")
 		} else {
 			metrics.bonusProposerRewardGauge.Set(value)
+			metrics.distributionBonusProposerRewardGauge.Set(value)
 		}
 
-		if value, err := strconv.ParseFloat(paramsResponse.Params.CommunityTax.String(), 64); err != nil {
+		if value, err := DecToFloat64(paramsResponse.Params.CommunityTax); err != nil {
 			sublogger.Error().
 				Err(err).
 				Msg("Could not parse community rate")
 		} else {
 			metrics.communityTaxGauge.Set(value)
+			metrics.distributionCommunityTaxGauge.Set(value)
 		}
 	}()
 	wg.Add(1)
 
 }
 func (s *Service) ParamsHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/params")
 	requestStart := time.Now()
 
 	sublogger := s.Log.With().
-		Str("request-id", uuid.New().String()).
+		Str("request-id", RequestID(r)).
 		Logger()
 
-	registry := prometheus.NewRegistry()
+	registry := s.Config.NewRegistry()
 	paramsMetrics := NewParamsMetrics(registry, s.Config)
 
 	var wg sync.WaitGroup
@@ -367,8 +391,7 @@ func (s *Service) ParamsHandler(w http.ResponseWriter, r *http.Request) {
 
 	wg.Wait()
 
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	h.ServeHTTP(w, r)
+	s.ServeMetrics(w, r, registry)
 	sublogger.Info().
 		Str("method", "GET").
 		Str("endpoint", "/metrics/params").
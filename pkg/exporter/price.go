@@ -0,0 +1,62 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// cachedPrice is the last USD price fetched from --price-api-url, kept for --price-ttl
+type cachedPrice struct {
+	value   float64
+	fetched time.Time
+}
+
+// GetPriceUSD returns the USD price of the token from --price-api-url, fetching it again once --price-ttl has passed
+func (s *Service) GetPriceUSD() (float64, error) {
+	s.priceMutex.Lock()
+	defer s.priceMutex.Unlock()
+
+	if !s.price.fetched.IsZero() && time.Since(s.price.fetched) < s.Config.PriceTTL {
+		return s.price.value, nil
+	}
+
+	price, err := fetchPriceUSD(s.Config.PriceAPIURL)
+	if err != nil {
+		return 0, err
+	}
+
+	s.price = cachedPrice{value: price, fetched: time.Now()}
+	return price, nil
+}
+
+// fetchPriceUSD queries an API answering in the CoinGecko simple price format, like
+// https://api.coingecko.com/api/v3/simple/price?ids=cosmos&vs_currencies=usd which returns {"cosmos":{"usd":9.5}}
+func fetchPriceUSD(url string) (float64, error) {
+	httpClient := &http.Client{Timeout: 2 * time.Second}
+	r, err := httpClient.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("price API returned %s", r.Status)
+	}
+
+	var resp map[string]map[string]float64
+	if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+		return 0, err
+	}
+	if len(resp) != 1 {
+		return 0, fmt.Errorf("expected the price of 1 token, got %d", len(resp))
+	}
+
+	for _, prices := range resp {
+		if price, ok := prices["usd"]; ok {
+			return price, nil
+		}
+	}
+	return 0, fmt.Errorf("no usd price in the response")
+}
@@ -10,19 +10,22 @@ import (
 	"github.com/cosmos/cosmos-sdk/types/query"
 	"github.com/rs/zerolog"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	govtypeV1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
 	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
-	"github.com/google/uuid"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type ProposalsMetrics struct {
-	proposalsGauge *prometheus.GaugeVec
+	proposalsGauge        *prometheus.GaugeVec
+	quorumReachedGauge    *prometheus.GaugeVec
+	depositRemainingGauge *prometheus.GaugeVec
+	depositEndTimeGauge   *prometheus.GaugeVec
 }
 type ValidatorVotingMetrics struct {
 	validatorVoting *prometheus.GaugeVec
@@ -42,8 +45,35 @@ func NewProposalsMetrics(reg prometheus.Registerer, config *ServiceConfig) *Prop
 			},
 			[]string{"title", "status", "voting_start_time", "voting_end_time"},
 		),
+		quorumReachedGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_gov_proposal_quorum_reached",
+				Help:        "1 if the voting period proposal has reached quorum, 0 if no",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"proposal_id"},
+		),
+		depositRemainingGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_gov_proposal_deposit_remaining",
+				Help:        "Deposit still needed for the deposit period proposal to enter voting period",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"proposal_id", "denom"},
+		),
+		depositEndTimeGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_gov_proposal_deposit_end_time",
+				Help:        "Deposit end time of the deposit period proposal, as unix timestamp",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"proposal_id"},
+		),
 	}
 	reg.MustRegister(m.proposalsGauge)
+	reg.MustRegister(m.quorumReachedGauge)
+	reg.MustRegister(m.depositRemainingGauge)
+	reg.MustRegister(m.depositEndTimeGauge)
 	return m
 }
 func NewValidatorVotingMetrics(reg prometheus.Registerer, config *ServiceConfig) *ValidatorVotingMetrics {
@@ -70,18 +100,11 @@ func GetProposalsMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics
 			sublogger.Debug().Msg("Started querying v1 proposals")
 			queryStart := time.Now()
 
-			govClient := govtypeV1.NewQueryClient(s.GrpcConn)
-
-			var propReq govtypeV1.QueryProposalsRequest
+			status := govtypeV1.StatusNil
 			if activeOnly {
-				propReq = govtypeV1.QueryProposalsRequest{ProposalStatus: govtypeV1.StatusVotingPeriod, Pagination: &query.PageRequest{Reverse: true}}
-			} else {
-				propReq = govtypeV1.QueryProposalsRequest{Pagination: &query.PageRequest{Reverse: true}}
+				status = govtypeV1.StatusVotingPeriod
 			}
-			proposalsResponse, err := govClient.Proposals(
-				context.Background(),
-				&propReq,
-			)
+			proposals, err := s.queryProposalsV1(status)
 			if err != nil {
 				sublogger.Error().Err(err).Msg("Could not get proposals")
 				return
@@ -90,12 +113,20 @@ func GetProposalsMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics
 			sublogger.Debug().
 				Float64("request-time", time.Since(queryStart).Seconds()).
 				Msg("Finished querying proposals")
-			var proposals = proposalsResponse.Proposals
 
 			sublogger.Debug().
 				Int("proposalsLength", len(proposals)).
 				Msg("Proposals info")
 
+			// the quorum of the voting period proposals is computed from the same snapshot instead of querying them again
+			var votingProposals []uint64
+			for _, proposal := range proposals {
+				if proposal.Status == govtypeV1.StatusVotingPeriod {
+					votingProposals = append(votingProposals, proposal.Id)
+				}
+			}
+			getProposalsQuorumMetrics(wg, sublogger, metrics, s, config, votingProposals)
+
 			//cdcRegistry := codectypes.NewInterfaceRegistry()
 			//cdc := codec.NewProtoCodec(cdcRegistry)
 			for _, proposal := range proposals {
@@ -146,23 +177,14 @@ func GetProposalsMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics
 		go func() {
 			defer wg.Done()
 
-			var proposals []govtypes.Proposal
-
 			sublogger.Debug().Msg("Started querying v1beta1 proposals")
 			queryStart := time.Now()
 
-			govClient := govtypes.NewQueryClient(s.GrpcConn)
-
-			var propReq govtypes.QueryProposalsRequest
+			status := govtypes.StatusNil
 			if activeOnly {
-				propReq = govtypes.QueryProposalsRequest{ProposalStatus: govtypes.StatusVotingPeriod, Pagination: &query.PageRequest{Reverse: true}}
-			} else {
-				propReq = govtypes.QueryProposalsRequest{Pagination: &query.PageRequest{Reverse: true}}
+				status = govtypes.StatusVotingPeriod
 			}
-			proposalsResponse, err := govClient.Proposals(
-				context.Background(),
-				&propReq,
-			)
+			proposals, err := s.queryProposals(status)
 			if err != nil {
 				sublogger.Error().Err(err).Msg("Could not get proposals")
 				return
@@ -171,12 +193,20 @@ func GetProposalsMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics
 			sublogger.Debug().
 				Float64("request-time", time.Since(queryStart).Seconds()).
 				Msg("Finished querying proposals")
-			proposals = proposalsResponse.Proposals
 
 			sublogger.Debug().
 				Int("proposalsLength", len(proposals)).
 				Msg("Proposals info")
 
+			// the quorum of the voting period proposals is computed from the same snapshot instead of querying them again
+			var votingProposals []uint64
+			for _, proposal := range proposals {
+				if proposal.Status == govtypes.StatusVotingPeriod {
+					votingProposals = append(votingProposals, proposal.ProposalId)
+				}
+			}
+			getProposalsQuorumMetrics(wg, sublogger, metrics, s, config, votingProposals)
+
 			cdcRegistry := codectypes.NewInterfaceRegistry()
 			cdc := codec.NewProtoCodec(cdcRegistry)
 			for _, proposal := range proposals {
@@ -201,6 +231,247 @@ func GetProposalsMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics
 			}
 		}()
 	}
+
+	getProposalsDepositMetrics(wg, sublogger, metrics, s, config)
+}
+
+// getProposalsQuorumMetrics serves whether the voting period proposals, already fetched by GetProposalsMetrics, reached quorum
+func getProposalsQuorumMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *ProposalsMetrics, s *Service, config *ServiceConfig, activeProps []uint64) {
+	if len(activeProps) == 0 {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		sublogger.Debug().Msg("Started querying proposals quorum")
+		queryStart := time.Now()
+
+		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
+		poolResponse, err := stakingClient.Pool(
+			context.Background(),
+			&stakingtypes.QueryPoolRequest{},
+		)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get staking pool")
+			return
+		}
+		bondedTokens := poolResponse.Pool.BondedTokens
+		if !bondedTokens.IsPositive() {
+			sublogger.Warn().Msg("No bonded tokens, cannot compute quorum")
+			return
+		}
+
+		quorum, err := s.getGovQuorum(config)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get gov tally params")
+			return
+		}
+
+		for _, id := range activeProps {
+			voted, err := s.getProposalTallyTotal(config, id)
+			if err != nil {
+				sublogger.Error().
+					Str("proposal_id", fmt.Sprint(id)).
+					Err(err).
+					Msg("Could not get proposal tally")
+				continue
+			}
+
+			// golang doesn't have a ternary operator, so we have to stick with this ugly solution
+			var reached float64
+			if types.NewDecFromInt(voted).Quo(types.NewDecFromInt(bondedTokens)).GTE(quorum) {
+				reached = 1
+			} else {
+				reached = 0
+			}
+
+			metrics.quorumReachedGauge.With(prometheus.Labels{
+				"proposal_id": fmt.Sprint(id),
+			}).Set(reached)
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying proposals quorum")
+	}()
+}
+
+func getProposalsDepositMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *ProposalsMetrics, s *Service, config *ServiceConfig) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		sublogger.Debug().Msg("Started querying deposit period proposals")
+		queryStart := time.Now()
+
+		minDeposit, err := s.getGovMinDeposit(config)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get gov deposit params")
+			return
+		}
+
+		// proposal id -> total deposit and deposit end time
+		totalDeposits := map[uint64]types.Coins{}
+		depositEndTimes := map[uint64]time.Time{}
+
+		if config.PropV1 {
+			proposals, err := s.queryProposalsV1(govtypeV1.StatusDepositPeriod)
+			if err != nil {
+				sublogger.Error().Err(err).Msg("Could not get deposit period proposals")
+				return
+			}
+			for _, proposal := range proposals {
+				totalDeposits[proposal.Id] = types.Coins(proposal.TotalDeposit)
+				if proposal.DepositEndTime != nil {
+					depositEndTimes[proposal.Id] = *proposal.DepositEndTime
+				}
+			}
+		} else {
+			proposals, err := s.queryProposals(govtypes.StatusDepositPeriod)
+			if err != nil {
+				sublogger.Error().Err(err).Msg("Could not get deposit period proposals")
+				return
+			}
+			for _, proposal := range proposals {
+				totalDeposits[proposal.ProposalId] = proposal.TotalDeposit
+				depositEndTimes[proposal.ProposalId] = proposal.DepositEndTime
+			}
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int("proposalsLength", len(totalDeposits)).
+			Msg("Finished querying deposit period proposals")
+
+		for id, totalDeposit := range totalDeposits {
+			for _, coin := range minDeposit {
+				remaining := coin.Amount.Sub(totalDeposit.AmountOf(coin.Denom))
+				if remaining.IsNegative() {
+					remaining = types.ZeroInt()
+				}
+
+				// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+				if value, err := strconv.ParseFloat(remaining.String(), 64); err != nil {
+					sublogger.Error().
+						Str("proposal_id", fmt.Sprint(id)).
+						Err(err).
+						Msg("Could not parse remaining deposit")
+				} else {
+					metrics.depositRemainingGauge.With(prometheus.Labels{
+						"proposal_id": fmt.Sprint(id),
+						"denom":       coin.Denom,
+					}).Set(value / config.DenomCoefficient)
+				}
+			}
+
+			if endTime, ok := depositEndTimes[id]; ok {
+				metrics.depositEndTimeGauge.With(prometheus.Labels{
+					"proposal_id": fmt.Sprint(id),
+				}).Set(float64(endTime.Unix()))
+			}
+		}
+	}()
+}
+
+// getGovMinDeposit returns the deposit a proposal needs to enter voting period
+func (s *Service) getGovMinDeposit(config *ServiceConfig) (types.Coins, error) {
+	if config.PropV1 {
+		govClient := govtypeV1.NewQueryClient(s.GrpcConn)
+		paramsResponse, err := govClient.Params(
+			context.Background(),
+			&govtypeV1.QueryParamsRequest{ParamsType: govtypeV1.ParamDeposit},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if paramsResponse.DepositParams == nil {
+			return nil, fmt.Errorf("no deposit params in response")
+		}
+		return types.Coins(paramsResponse.DepositParams.MinDeposit), nil
+	}
+
+	govClient := govtypes.NewQueryClient(s.GrpcConn)
+	paramsResponse, err := govClient.Params(
+		context.Background(),
+		&govtypes.QueryParamsRequest{ParamsType: govtypes.ParamDeposit},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return paramsResponse.DepositParams.MinDeposit, nil
+}
+
+// getGovQuorum returns the minimum share of bonded stake that has to vote for a proposal to be valid
+func (s *Service) getGovQuorum(config *ServiceConfig) (types.Dec, error) {
+	if config.PropV1 {
+		govClient := govtypeV1.NewQueryClient(s.GrpcConn)
+		paramsResponse, err := govClient.Params(
+			context.Background(),
+			&govtypeV1.QueryParamsRequest{ParamsType: govtypeV1.ParamTallying},
+		)
+		if err != nil {
+			return types.Dec{}, err
+		}
+		if paramsResponse.TallyParams == nil {
+			return types.Dec{}, fmt.Errorf("no tally params in response")
+		}
+		return types.NewDecFromStr(paramsResponse.TallyParams.Quorum)
+	}
+
+	govClient := govtypes.NewQueryClient(s.GrpcConn)
+	paramsResponse, err := govClient.Params(
+		context.Background(),
+		&govtypes.QueryParamsRequest{ParamsType: govtypes.ParamTallying},
+	)
+	if err != nil {
+		return types.Dec{}, err
+	}
+	return paramsResponse.TallyParams.Quorum, nil
+}
+
+// getProposalTallyTotal returns the sum of all the votes cast on a proposal, abstain included
+func (s *Service) getProposalTallyTotal(config *ServiceConfig, id uint64) (types.Int, error) {
+	if config.PropV1 {
+		govClient := govtypeV1.NewQueryClient(s.GrpcConn)
+		tallyResponse, err := govClient.TallyResult(
+			context.Background(),
+			&govtypeV1.QueryTallyResultRequest{ProposalId: id},
+		)
+		if err != nil {
+			return types.Int{}, err
+		}
+		if tallyResponse.Tally == nil {
+			return types.ZeroInt(), nil
+		}
+
+		total := types.ZeroInt()
+		for _, count := range []string{
+			tallyResponse.Tally.YesCount,
+			tallyResponse.Tally.NoCount,
+			tallyResponse.Tally.AbstainCount,
+			tallyResponse.Tally.NoWithVetoCount,
+		} {
+			value, ok := types.NewIntFromString(count)
+			if !ok {
+				return types.Int{}, fmt.Errorf("could not parse tally count %q", count)
+			}
+			total = total.Add(value)
+		}
+		return total, nil
+	}
+
+	govClient := govtypes.NewQueryClient(s.GrpcConn)
+	tallyResponse, err := govClient.TallyResult(
+		context.Background(),
+		&govtypes.QueryTallyResultRequest{ProposalId: id},
+	)
+	if err != nil {
+		return types.Int{}, err
+	}
+	tally := tallyResponse.Tally
+	return tally.Yes.Add(tally.No).Add(tally.Abstain).Add(tally.NoWithVeto), nil
 }
 func GetProposalsVoteMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *ValidatorVotingMetrics, s *Service, _ *ServiceConfig, id uint64, validator types.ValAddress, wallet types.AccAddress) {
 
@@ -257,16 +528,7 @@ func (s *Service) GetActiveProposalsV1(sublogger *zerolog.Logger) ([]uint64, err
 	sublogger.Debug().Msg("Started querying v1 proposals")
 	queryStart := time.Now()
 
-	govClient := govtypeV1.NewQueryClient(s.GrpcConn)
-
-	var propReq govtypeV1.QueryProposalsRequest
-
-	propReq = govtypeV1.QueryProposalsRequest{ProposalStatus: govtypeV1.StatusVotingPeriod, Pagination: &query.PageRequest{Reverse: true}}
-
-	proposalsResponse, err := govClient.Proposals(
-		context.Background(),
-		&propReq,
-	)
+	proposalsResponse, err := s.queryProposalsV1(govtypeV1.StatusVotingPeriod)
 	if err != nil {
 		sublogger.Error().Err(err).Msg("Could not get proposals")
 		return nil, err
@@ -278,7 +540,7 @@ func (s *Service) GetActiveProposalsV1(sublogger *zerolog.Logger) ([]uint64, err
 
 	//var x = [];
 	var proposals []uint64
-	for _, prop := range proposalsResponse.Proposals {
+	for _, prop := range proposalsResponse {
 		if prop.Status == govtypeV1.ProposalStatus_PROPOSAL_STATUS_VOTING_PERIOD {
 			proposals = append(proposals, prop.Id)
 		}
@@ -290,16 +552,7 @@ func (s *Service) GetActiveProposals(sublogger *zerolog.Logger) ([]uint64, error
 	sublogger.Debug().Msg("Started querying v1 proposals")
 	queryStart := time.Now()
 
-	govClient := govtypes.NewQueryClient(s.GrpcConn)
-
-	var propReq govtypes.QueryProposalsRequest
-
-	propReq = govtypes.QueryProposalsRequest{ProposalStatus: govtypes.StatusVotingPeriod, Pagination: &query.PageRequest{Reverse: true}}
-
-	proposalsResponse, err := govClient.Proposals(
-		context.Background(),
-		&propReq,
-	)
+	proposalsResponse, err := s.queryProposals(govtypes.StatusVotingPeriod)
 	if err != nil {
 		sublogger.Error().Err(err).Msg("Could not get proposals")
 		return nil, err
@@ -309,7 +562,7 @@ func (s *Service) GetActiveProposals(sublogger *zerolog.Logger) ([]uint64, error
 		Float64("request-time", time.Since(queryStart).Seconds()).
 		Msg("Finished querying proposals")
 	var proposals []uint64
-	for _, prop := range proposalsResponse.Proposals {
+	for _, prop := range proposalsResponse {
 		if prop.Status == govtypes.StatusVotingPeriod {
 			proposals = append(proposals, prop.ProposalId)
 		}
@@ -317,14 +570,60 @@ func (s *Service) GetActiveProposals(sublogger *zerolog.Logger) ([]uint64, error
 	return proposals, nil
 
 }
+
+// queryProposalsV1 returns the v1 proposals with the status, or all of them for StatusNil, from all the pages,
+// newest first
+func (s *Service) queryProposalsV1(status govtypeV1.ProposalStatus) ([]*govtypeV1.Proposal, error) {
+	govClient := govtypeV1.NewQueryClient(s.GrpcConn)
+	return Paginate(func(key []byte) ([]*govtypeV1.Proposal, []byte, error) {
+		proposalsResponse, err := govClient.Proposals(
+			context.Background(),
+			&govtypeV1.QueryProposalsRequest{
+				ProposalStatus: status,
+				Pagination: &query.PageRequest{
+					Key:     key,
+					Limit:   s.Config.Limit,
+					Reverse: true,
+				},
+			},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		return proposalsResponse.Proposals, proposalsResponse.Pagination.GetNextKey(), nil
+	})
+}
+
+// queryProposals is queryProposalsV1 for the v1beta1 proposals
+func (s *Service) queryProposals(status govtypes.ProposalStatus) ([]govtypes.Proposal, error) {
+	govClient := govtypes.NewQueryClient(s.GrpcConn)
+	return Paginate(func(key []byte) ([]govtypes.Proposal, []byte, error) {
+		proposalsResponse, err := govClient.Proposals(
+			context.Background(),
+			&govtypes.QueryProposalsRequest{
+				ProposalStatus: status,
+				Pagination: &query.PageRequest{
+					Key:     key,
+					Limit:   s.Config.Limit,
+					Reverse: true,
+				},
+			},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		return proposalsResponse.Proposals, proposalsResponse.Pagination.GetNextKey(), nil
+	})
+}
 func (s *Service) ProposalsHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/proposals")
 	requestStart := time.Now()
 
 	sublogger := s.Log.With().
-		Str("request-id", uuid.New().String()).
+		Str("request-id", RequestID(r)).
 		Logger()
 
-	registry := prometheus.NewRegistry()
+	registry := s.Config.NewRegistry()
 	proposalsMetrics := NewProposalsMetrics(registry, s.Config)
 
 	var wg sync.WaitGroup
@@ -332,8 +631,7 @@ func (s *Service) ProposalsHandler(w http.ResponseWriter, r *http.Request) {
 	GetProposalsMetrics(&wg, &sublogger, proposalsMetrics, s, s.Config, false)
 
 	wg.Wait()
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	h.ServeHTTP(w, r)
+	s.ServeMetrics(w, r, registry)
 	sublogger.Info().
 		Str("method", "GET").
 		Str("endpoint", "/metrics/proposals").
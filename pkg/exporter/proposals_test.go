@@ -0,0 +1,110 @@
+package exporter_test
+
+import (
+	"context"
+	"fmt"
+	"main/pkg/exporter"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// pagedGovServer serves one proposal per page, so a one page query only gets the newest one
+type pagedGovServer struct {
+	govtypes.UnimplementedQueryServer
+	t *testing.T
+
+	mutex    sync.Mutex
+	statuses []govtypes.ProposalStatus
+}
+
+func (server *pagedGovServer) Proposals(_ context.Context, request *govtypes.QueryProposalsRequest) (*govtypes.QueryProposalsResponse, error) {
+	server.mutex.Lock()
+	server.statuses = append(server.statuses, request.ProposalStatus)
+	server.mutex.Unlock()
+
+	pages := map[string]struct {
+		id      uint64
+		nextKey []byte
+	}{
+		"":       {id: 3, nextKey: []byte("second")},
+		"second": {id: 2, nextKey: []byte("third")},
+		"third":  {id: 1},
+	}
+	page := pages[string(request.Pagination.GetKey())]
+
+	content, err := codectypes.NewAnyWithValue(&govtypes.TextProposal{Title: fmt.Sprintf("proposal %d", page.id)})
+	require.NoError(server.t, err)
+	return &govtypes.QueryProposalsResponse{
+		Proposals:  []govtypes.Proposal{{ProposalId: page.id, Content: content, Status: govtypes.StatusVotingPeriod}},
+		Pagination: &querytypes.PageResponse{NextKey: page.nextKey},
+	}, nil
+}
+
+func (*pagedGovServer) Params(_ context.Context, request *govtypes.QueryParamsRequest) (*govtypes.QueryParamsResponse, error) {
+	if request.ParamsType != govtypes.ParamTallying {
+		return nil, status.Error(codes.Unimplemented, "only the tally params are served")
+	}
+	return &govtypes.QueryParamsResponse{TallyParams: govtypes.TallyParams{Quorum: sdk.MustNewDecFromStr("0.4")}}, nil
+}
+
+// TallyResult has proposal 2 reach quorum with a half of the bonded tokens
+func (*pagedGovServer) TallyResult(_ context.Context, request *govtypes.QueryTallyResultRequest) (*govtypes.QueryTallyResultResponse, error) {
+	yes := sdk.NewInt(100)
+	if request.ProposalId == 2 {
+		yes = sdk.NewInt(500)
+	}
+	return &govtypes.QueryTallyResultResponse{Tally: govtypes.NewTallyResult(yes, sdk.ZeroInt(), sdk.ZeroInt(), sdk.ZeroInt())}, nil
+}
+
+type poolStakingServer struct {
+	emptyStakingServer
+}
+
+func (poolStakingServer) Pool(context.Context, *stakingtypes.QueryPoolRequest) (*stakingtypes.QueryPoolResponse, error) {
+	return &stakingtypes.QueryPoolResponse{Pool: stakingtypes.NewPool(sdk.ZeroInt(), sdk.NewInt(1000))}, nil
+}
+
+func TestProposalsHandlerQuorumSameSnapshot(t *testing.T) {
+	govServer := &pagedGovServer{t: t}
+	s := newTestService(t, func(server *grpc.Server) {
+		govtypes.RegisterQueryServer(server, govServer)
+		stakingtypes.RegisterQueryServer(server, &poolStakingServer{})
+	}, &exporter.ServiceConfig{Limit: 1, DenomCoefficient: 1})
+
+	recorder := httptest.NewRecorder()
+	s.ProposalsHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/proposals", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `cosmos_gov_proposal_quorum_reached{proposal_id="1"} 0`+"\n")
+	require.Contains(t, recorder.Body.String(), `cosmos_gov_proposal_quorum_reached{proposal_id="2"} 1`+"\n")
+	require.Contains(t, recorder.Body.String(), `cosmos_gov_proposal_quorum_reached{proposal_id="3"} 0`+"\n")
+
+	// the three pages of all the proposals, the voting period ones aren't queried again for the quorum
+	require.Equal(t, []govtypes.ProposalStatus{govtypes.StatusNil, govtypes.StatusNil, govtypes.StatusNil}, govServer.statuses)
+}
+
+func TestProposalsHandlerPages(t *testing.T) {
+	s := newTestService(t, func(server *grpc.Server) {
+		govtypes.RegisterQueryServer(server, &pagedGovServer{t: t})
+	}, &exporter.ServiceConfig{Limit: 1, DenomCoefficient: 1})
+
+	recorder := httptest.NewRecorder()
+	s.ProposalsHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/proposals", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	for _, id := range []string{"1", "2", "3"} {
+		require.Contains(t, recorder.Body.String(), `title="proposal `+id+`",voting_end_time="0001-01-01 00:00:00 +0000 UTC",voting_start_time="0001-01-01 00:00:00 +0000 UTC"} `+id+"\n")
+	}
+}
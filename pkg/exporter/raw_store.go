@@ -0,0 +1,138 @@
+package exporter
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"github.com/rs/zerolog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type RawStoreMetrics struct {
+	valueBytesGauge *prometheus.GaugeVec
+	valueGauge      *prometheus.GaugeVec
+}
+
+func NewRawStoreMetrics(reg prometheus.Registerer, config *ServiceConfig) *RawStoreMetrics {
+	m := &RawStoreMetrics{
+		valueBytesGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_raw_store_value_bytes",
+				Help:        "Length of the raw value of the store key, 0 if it isn't set",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"path", "key"},
+		),
+		valueGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_raw_store_value",
+				Help:        "Raw value of the store key parsed as an integer",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"path", "key"},
+		),
+	}
+	reg.MustRegister(m.valueBytesGauge)
+	reg.MustRegister(m.valueGauge)
+	return m
+}
+func GetRawStoreMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *RawStoreMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying raw store")
+		queryStart := time.Now()
+
+		key, err := hex.DecodeString(config.RawStoreKey)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not decode --raw-store-key")
+			return
+		}
+
+		cs, err := s.chainStatus()
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get chain status")
+			return
+		}
+
+		result, err := cs.client.ABCIQuery(context.Background(), config.RawStorePath, key)
+		cs.queries.record(err)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not query raw store")
+			return
+		}
+		if !result.Response.IsOK() {
+			sublogger.Error().
+				Uint32("code", result.Response.Code).
+				Str("log", result.Response.Log).
+				Msg("Could not query raw store")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int64("height", result.Response.Height).
+			Msg("Finished querying raw store")
+
+		labels := prometheus.Labels{
+			"path": config.RawStorePath,
+			"key":  config.RawStoreKey,
+		}
+		metrics.valueBytesGauge.With(labels).Set(float64(len(result.Response.Value)))
+
+		if !config.RawStoreInt || len(result.Response.Value) == 0 {
+			return
+		}
+
+		value, err := ParseRawInt(result.Response.Value)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not parse raw store value")
+			return
+		}
+		metrics.valueGauge.With(labels).Set(value)
+	}()
+
+}
+
+// ParseRawInt parses a store value as an integer. The SDK stores math.Int as its decimal text,
+// and the counters like the next IDs as big-endian uint64, so both are accepted.
+func ParseRawInt(value []byte) (float64, error) {
+	if number, ok := new(big.Int).SetString(string(value), 10); ok {
+		float, _ := new(big.Float).SetInt(number).Float64()
+		return float, nil
+	}
+	if len(value) == 8 {
+		return float64(binary.BigEndian.Uint64(value)), nil
+	}
+	return 0, fmt.Errorf("value %X is neither a decimal nor a big-endian uint64", value)
+}
+func (s *Service) RawStoreHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/raw-store")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	rawStoreMetrics := NewRawStoreMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetRawStoreMetrics(&wg, &sublogger, rawStoreMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/raw-store").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
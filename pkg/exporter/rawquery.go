@@ -0,0 +1,169 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// rawCodec passes already encoded protobuf messages through to gRPC untouched.
+// it allows querying modules we don't have the go types for (feemarket, wasm, ...) without pulling
+// in their whole dependency tree, which usually doesn't build against our cosmos-sdk version
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	message, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("raw codec cannot marshal %T", v)
+	}
+	return *message, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	message, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("raw codec cannot unmarshal into %T", v)
+	}
+	*message = append((*message)[:0], data...)
+	return nil
+}
+
+// Name has to be "proto" as it's sent as the content-subtype, and the node only knows that one
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// rawQuery calls a gRPC query by its full method name, e.g. "/osmosis.txfees.v1beta1.Query/GetEip1559BaseFee",
+// with an already encoded request, and returns the encoded response
+func (s *Service) rawQuery(ctx context.Context, method string, request []byte) ([]byte, error) {
+	var response []byte
+	err := s.GrpcConn.Invoke(ctx, method, &request, &response, grpc.ForceCodec(rawCodec{}))
+	return response, err
+}
+
+// rawBytesFields returns the value of every occurrence of the length-delimited (string, bytes or message) field in the message,
+// and errors out if the field has another wire type, as it means we got the schema of the response wrong
+func rawBytesFields(message []byte, field protowire.Number) ([][]byte, error) {
+	var values [][]byte
+	for len(message) > 0 {
+		number, wireType, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		message = message[n:]
+
+		if number == field {
+			if wireType != protowire.BytesType {
+				return nil, fmt.Errorf("field %d has wire type %d, expected a length-delimited one", field, wireType)
+			}
+			value, n := protowire.ConsumeBytes(message)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			values = append(values, value)
+			message = message[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(number, wireType, message)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		message = message[n:]
+	}
+	return values, nil
+}
+
+// rawBytesField returns the last occurrence of the length-delimited field in the message, or nil if it's not set
+func rawBytesField(message []byte, field protowire.Number) ([]byte, error) {
+	values, err := rawBytesFields(message, field)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	return values[len(values)-1], nil
+}
+
+// rawVarintField returns the last occurrence of the varint (uint64, int64, bool, enum) field in the message, or 0 if it's not set
+func rawVarintField(message []byte, field protowire.Number) (uint64, error) {
+	var value uint64
+	for len(message) > 0 {
+		number, wireType, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		message = message[n:]
+
+		if number == field {
+			if wireType != protowire.VarintType {
+				return 0, fmt.Errorf("field %d has wire type %d, expected a varint", field, wireType)
+			}
+			v, n := protowire.ConsumeVarint(message)
+			if n < 0 {
+				return 0, protowire.ParseError(n)
+			}
+			value = v
+			message = message[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(number, wireType, message)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		message = message[n:]
+	}
+	return value, nil
+}
+
+// rawDecField decodes a sdk.Dec field, which gogoproto sends as the string of its 10^18 scaled integer
+func rawDecField(message []byte, field protowire.Number) (sdk.Dec, error) {
+	value, err := rawBytesField(message, field)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+	dec := sdk.ZeroDec()
+	if len(value) == 0 {
+		return dec, nil
+	}
+	err = dec.Unmarshal(value)
+	return dec, err
+}
+
+// rawTimeField decodes a google.protobuf.Timestamp field
+func rawTimeField(message []byte, field protowire.Number) (time.Time, error) {
+	seconds, nanos, err := rawSecondsNanos(message, field)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, nanos).UTC(), nil
+}
+
+// rawDurationField decodes a google.protobuf.Duration field
+func rawDurationField(message []byte, field protowire.Number) (time.Duration, error) {
+	seconds, nanos, err := rawSecondsNanos(message, field)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds)*time.Second + time.Duration(nanos), nil
+}
+
+// rawSecondsNanos decodes the int64 seconds = 1 and int32 nanos = 2 fields shared by Timestamp and Duration
+func rawSecondsNanos(message []byte, field protowire.Number) (int64, int64, error) {
+	value, err := rawBytesField(message, field)
+	if err != nil {
+		return 0, 0, err
+	}
+	seconds, err := rawVarintField(value, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	nanos, err := rawVarintField(value, 2)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int64(seconds), int64(nanos), nil
+}
@@ -0,0 +1,180 @@
+package exporter_test
+
+import (
+	"main/pkg/exporter"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func appendBytesField(message []byte, field protowire.Number, value []byte) []byte {
+	message = protowire.AppendTag(message, field, protowire.BytesType)
+	return protowire.AppendBytes(message, value)
+}
+
+func appendVarintField(message []byte, field protowire.Number, value uint64) []byte {
+	message = protowire.AppendTag(message, field, protowire.VarintType)
+	return protowire.AppendVarint(message, value)
+}
+
+// secondsNanos encodes a google.protobuf.Timestamp or Duration
+func secondsNanos(seconds int64, nanos int32) []byte {
+	return appendVarintField(appendVarintField(nil, 1, uint64(seconds)), 2, uint64(int64(nanos)))
+}
+
+func TestRawBytesFields(t *testing.T) {
+	message := appendBytesField(nil, 1, []byte("first"))
+	message = appendVarintField(message, 2, 42)
+	message = appendBytesField(message, 1, []byte("second"))
+	message = appendBytesField(message, 3, []byte("other"))
+
+	for _, test := range []struct {
+		name     string
+		message  []byte
+		field    protowire.Number
+		expected [][]byte
+		err      bool
+	}{
+		{name: "repeated", message: message, field: 1, expected: [][]byte{[]byte("first"), []byte("second")}},
+		{name: "single", message: message, field: 3, expected: [][]byte{[]byte("other")}},
+		{name: "missing", message: message, field: 4},
+		{name: "empty message", message: nil, field: 1},
+		{name: "truncated", message: message[:len(message)-1], field: 1, err: true},
+		{name: "wrong wire type", message: message, field: 2, err: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := exporter.RawBytesFields(test.message, test.field)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, values)
+		})
+	}
+}
+
+func TestRawBytesField(t *testing.T) {
+	message := appendBytesField(appendBytesField(nil, 1, []byte("first")), 1, []byte("last"))
+
+	// the last occurrence wins, like for the scalar fields of a decoded message
+	value, err := exporter.RawBytesField(message, 1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("last"), value)
+
+	value, err = exporter.RawBytesField(message, 2)
+	require.NoError(t, err)
+	require.Nil(t, value)
+}
+
+func TestRawVarintField(t *testing.T) {
+	message := appendVarintField(nil, 1, 10)
+	message = appendBytesField(message, 2, []byte("skipped"))
+	message = appendVarintField(message, 1, 20)
+	// int64 -1, sent as its two's complement
+	message = appendVarintField(message, 3, ^uint64(0))
+
+	for _, test := range []struct {
+		name     string
+		message  []byte
+		field    protowire.Number
+		expected uint64
+		err      bool
+	}{
+		{name: "repeated", message: message, field: 1, expected: 20},
+		{name: "negative", message: message, field: 3, expected: ^uint64(0)},
+		{name: "missing", message: message, field: 4},
+		{name: "truncated", message: message[:len(message)-1], field: 1, err: true},
+		{name: "wrong wire type", message: message, field: 2, err: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := exporter.RawVarintField(test.message, test.field)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func TestRawDecField(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		message  []byte
+		expected sdk.Dec
+		err      bool
+	}{
+		// gogoproto sends the 10^18 scaled integer
+		{name: "scaled", message: appendBytesField(nil, 1, []byte("1500000000000000000")), expected: sdk.MustNewDecFromStr("1.5")},
+		{name: "small", message: appendBytesField(nil, 1, []byte("2500000000000000")), expected: sdk.MustNewDecFromStr("0.0025")},
+		{name: "missing", message: appendBytesField(nil, 2, []byte("1500000000000000000")), expected: sdk.ZeroDec()},
+		{name: "not a number", message: appendBytesField(nil, 1, []byte("1.5")), err: true},
+		{name: "wrong wire type", message: appendVarintField(nil, 1, 15), err: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := exporter.RawDecField(test.message, 1)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, test.expected.Equal(value), "expected %s, got %s", test.expected, value)
+		})
+	}
+}
+
+func TestRawTimeField(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		message  []byte
+		expected time.Time
+		err      bool
+	}{
+		{name: "nanos", message: appendBytesField(nil, 1, secondsNanos(1700000000, 123456789)), expected: time.Unix(1700000000, 123456789).UTC()},
+		{name: "seconds only", message: appendBytesField(nil, 1, secondsNanos(1700000000, 0)), expected: time.Unix(1700000000, 0).UTC()},
+		{name: "missing", message: appendVarintField(nil, 2, 1), expected: time.Unix(0, 0).UTC()},
+		{name: "truncated", message: appendBytesField(nil, 1, secondsNanos(1700000000, 123456789))[:5], err: true},
+		{name: "wrong wire type", message: appendVarintField(nil, 1, 1700000000), err: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := exporter.RawTimeField(test.message, 1)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func TestRawDurationField(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		message  []byte
+		expected time.Duration
+		err      bool
+	}{
+		{name: "nanos", message: appendBytesField(nil, 1, secondsNanos(90, 500000000)), expected: 90*time.Second + 500*time.Millisecond},
+		// the seconds and nanos of a negative duration are both negative
+		{name: "negative", message: appendBytesField(nil, 1, secondsNanos(-1, -500000000)), expected: -1500 * time.Millisecond},
+		{name: "missing", message: nil},
+		{name: "truncated", message: appendBytesField(nil, 1, secondsNanos(90, 500000000))[:4], err: true},
+		{name: "wrong wire type", message: appendVarintField(nil, 1, 90), err: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := exporter.RawDurationField(test.message, 1)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, value)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package exporter
+
+import (
+	"context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// MaxRecentBlocks bounds --recent-blocks, as every block is an RPC query to the node on each scrape
+const MaxRecentBlocks = 500
+
+type RecentSignaturesMetrics struct {
+	signedRecentGauge *prometheus.GaugeVec
+	recentBlocksGauge prometheus.Gauge
+}
+
+func NewRecentSignaturesMetrics(reg prometheus.Registerer, config *ServiceConfig) *RecentSignaturesMetrics {
+	m := &RecentSignaturesMetrics{
+		signedRecentGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_signed_recent",
+				Help:        "Number of the recent blocks signed by the Cosmos-based blockchain validator",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		recentBlocksGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_signed_recent_blocks",
+				Help:        "Number of the recent blocks whose signatures were sampled",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+	}
+	reg.MustRegister(m.signedRecentGauge)
+	reg.MustRegister(m.recentBlocksGauge)
+	return m
+}
+func GetRecentSignaturesMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *RecentSignaturesMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying recent commits")
+		queryStart := time.Now()
+
+		cs, err := s.chainStatus()
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get chain status")
+			return
+		}
+
+		signed, blocks, err := cs.RecentSignatures(config.RecentBlocks)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get recent commits")
+			return
+		}
+
+		validators, err := s.GetValidators()
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get validators")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int("blocks", blocks).
+			Msg("Finished querying recent commits")
+
+		metrics.recentBlocksGauge.Set(float64(blocks))
+		for consAddress, validator := range ValidatorsByConsAddress(sublogger, validators) {
+			// the unbonded validators can't sign, but they can have signed some of the blocks before leaving the set
+			if !validator.IsBonded() && signed[consAddress] == 0 {
+				continue
+			}
+
+			metrics.signedRecentGauge.With(prometheus.Labels{
+				"address": validator.OperatorAddress,
+				"moniker": validator.Description.Moniker,
+			}).Set(float64(signed[consAddress]))
+		}
+	}()
+
+}
+
+// RecentSignatures counts the commit signatures of the last blocks by bech32 consensus address,
+// returned with the number of blocks sampled, which is less than asked for on freshly pruned nodes
+func (cs ChainStatus) RecentSignatures(blocks int) (map[string]int, int, error) {
+	info := cs.SyncInfo()
+	first := info.LatestBlockHeight - int64(blocks) + 1
+	if first < info.EarliestBlockHeight {
+		first = info.EarliestBlockHeight
+	}
+
+	signed := make(map[string]int)
+	sampled := 0
+	// one block at a time, to not hammer the node
+	for height := info.LatestBlockHeight; height >= first && height > 0; height-- {
+		result, err := cs.client.Commit(context.Background(), &height)
+		cs.queries.record(err)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, signature := range result.Commit.Signatures {
+			// nil votes don't count, as they didn't commit the block
+			if signature.BlockIDFlag != tmtypes.BlockIDFlagCommit {
+				continue
+			}
+			signed[sdk.ConsAddress(signature.ValidatorAddress).String()]++
+		}
+		sampled++
+	}
+	return signed, sampled, nil
+}
+func (s *Service) RecentSignaturesHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/recent-signatures")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	recentSignaturesMetrics := NewRecentSignaturesMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetRecentSignaturesMetrics(&wg, &sublogger, recentSignaturesMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/recent-signatures").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
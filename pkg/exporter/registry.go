@@ -0,0 +1,151 @@
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Registry is a prometheus registry which doesn't serve the metrics disabled with --disabled-metrics,
+// so collectors can keep populating their gauges without checking the config themselves
+type Registry struct {
+	*prometheus.Registry
+	disabled    map[string]bool
+	constLabels map[string]string
+}
+
+func (config *ServiceConfig) NewRegistry() *Registry {
+	disabled := make(map[string]bool, len(config.DisabledMetrics))
+	for _, name := range config.DisabledMetrics {
+		disabled[name] = true
+	}
+	return &Registry{Registry: prometheus.NewRegistry(), disabled: disabled, constLabels: config.ConstLabels}
+}
+
+// CheckConstLabels registers every metric set once, returning an error if one of the const labels collides with a metric label,
+// so it fails at startup instead of panicking in the middle of a scrape
+func (config *ServiceConfig) CheckConstLabels() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	// every set goes in its own registry, like in the handlers, as some of them share metric names
+	for _, newMetrics := range []func(prometheus.Registerer, *ServiceConfig){
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewGeneralMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewValidatorsMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewValidatorMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewValidatorExtendedMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewValidatorVotingMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewWalletMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewWalletExtendedMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewParamsMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewStakingMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewProposalsMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewUpgradeMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewGasMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewFeeMarketMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewOracleMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewIBCMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewAuthzMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewGroupMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewEpochsMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewWasmMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewWasmContractMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewRecentSignaturesMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewTotalDelegatorsMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewConsensusMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewConsensusParamsMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewValidatorSetMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewRawStoreMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewDenomMetadataMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewVestingMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewDelegatorDelegationsMetrics(reg, config) },
+		func(reg prometheus.Registerer, config *ServiceConfig) { NewExporterMetrics(reg, config) },
+	} {
+		newMetrics(config.NewRegistry(), config)
+	}
+	return nil
+}
+
+// MetricEnabled returns false if the metric was disabled with --disabled-metrics, to skip the queries only needed by it
+func (config *ServiceConfig) MetricEnabled(name string) bool {
+	for _, disabled := range config.DisabledMetrics {
+		if disabled == name {
+			return false
+		}
+	}
+	return true
+}
+
+// Register returns a readable error if a const label has the same name as a label of the collector's metrics,
+// prometheus only says "duplicate label names" without telling which const labels were set
+func (r *Registry) Register(collector prometheus.Collector) error {
+	err := r.Registry.Register(collector)
+	if err != nil && len(r.constLabels) > 0 && strings.Contains(err.Error(), "duplicate label names") {
+		names := make([]string, 0, len(r.constLabels))
+		for name := range r.constLabels {
+			names = append(names, fmt.Sprintf("%q", name))
+		}
+		sort.Strings(names)
+		return fmt.Errorf("one of the const labels %s collides with a metric label, rename it: %w", strings.Join(names, ", "), err)
+	}
+	return err
+}
+
+func (r *Registry) MustRegister(collectors ...prometheus.Collector) {
+	for _, collector := range collectors {
+		if err := r.Register(collector); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Gather leaves out the metrics disabled with --disabled-metrics
+func (r *Registry) Gather() ([]*dto.MetricFamily, error) {
+	families, err := r.Registry.Gather()
+	if len(r.disabled) == 0 {
+		return families, err
+	}
+
+	enabled := families[:0]
+	for _, family := range families {
+		if !r.disabled[family.GetName()] {
+			enabled = append(enabled, family)
+		}
+	}
+	return enabled, err
+}
+
+// MetricsAllowlist returns the metric names passed in the `metrics` query param, either comma separated or repeated
+func MetricsAllowlist(r *http.Request) map[string]bool {
+	allowed := make(map[string]bool)
+	for _, value := range r.URL.Query()["metrics"] {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				allowed[name] = true
+			}
+		}
+	}
+	return allowed
+}
+
+// FilterGatherer only returns the metric families named in allowed
+func FilterGatherer(gatherer prometheus.Gatherer, allowed map[string]bool) prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := gatherer.Gather()
+
+		filtered := families[:0]
+		for _, family := range families {
+			if allowed[family.GetName()] {
+				filtered = append(filtered, family)
+			}
+		}
+		return filtered, err
+	})
+}
@@ -0,0 +1,104 @@
+package exporter_test
+
+import (
+	"main/pkg/exporter"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryConstLabelsCollision(t *testing.T) {
+	config := &exporter.ServiceConfig{
+		ConstLabels: map[string]string{"chain_id": "cosmoshub-4", "moniker": "my-node"},
+	}
+	registry := config.NewRegistry()
+
+	gauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "cosmos_validators_jailed",
+			Help:        "Jailed status of the Cosmos-based blockchain validator",
+			ConstLabels: config.ConstLabels,
+		},
+		[]string{"address", "moniker"},
+	)
+
+	err := registry.Register(gauge)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `"chain_id", "moniker"`)
+	require.Contains(t, err.Error(), "cosmos_validators_jailed")
+
+	require.PanicsWithError(t, err.Error(), func() {
+		registry.MustRegister(gauge)
+	})
+}
+
+func TestRegistryConstLabelsNoCollision(t *testing.T) {
+	config := &exporter.ServiceConfig{
+		ConstLabels: map[string]string{"chain_id": "cosmoshub-4"},
+	}
+	registry := config.NewRegistry()
+
+	gauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "cosmos_validators_jailed",
+			Help:        "Jailed status of the Cosmos-based blockchain validator",
+			ConstLabels: config.ConstLabels,
+		},
+		[]string{"address", "moniker"},
+	)
+
+	require.NoError(t, registry.Register(gauge))
+}
+
+func TestRegistryDisabledMetrics(t *testing.T) {
+	config := &exporter.ServiceConfig{
+		DisabledMetrics: []string{"cosmos_validators_jailed"},
+	}
+	registry := config.NewRegistry()
+
+	jailedGauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "cosmos_validators_jailed", Help: "Jailed"})
+	tokensGauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "cosmos_validators_tokens", Help: "Tokens"})
+	registry.MustRegister(jailedGauge, tokensGauge)
+	jailedGauge.Set(1)
+	tokensGauge.Set(1)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	require.Equal(t, "cosmos_validators_tokens", families[0].GetName())
+}
+
+func TestCheckConstLabels(t *testing.T) {
+	config := &exporter.ServiceConfig{
+		ConstLabels: map[string]string{"chain_id": "cosmoshub-4"},
+	}
+	require.NoError(t, config.CheckConstLabels())
+
+	config.ConstLabels["denom"] = "uatom"
+	err := config.CheckConstLabels()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `"denom"`)
+}
+
+func TestCheckConstLabelsValidators(t *testing.T) {
+	// only used by the gauges of /metrics/validators
+	config := &exporter.ServiceConfig{
+		ConstLabels: map[string]string{"pubkey_hash": "ABCDEF"},
+	}
+	err := config.CheckConstLabels()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `"pubkey_hash"`)
+	require.Contains(t, err.Error(), "cosmos_validators_active")
+
+	config = &exporter.ServiceConfig{
+		ConstLabels: map[string]string{"status_name": "bonded"},
+	}
+	require.NoError(t, config.CheckConstLabels())
+
+	config.StatusNameLabel = true
+	err = config.CheckConstLabels()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `"status_name"`)
+	require.Contains(t, err.Error(), "cosmos_validators_status")
+}
@@ -0,0 +1,31 @@
+package exporter
+
+import (
+	"math/rand"
+	"time"
+)
+
+// retryBaseDelay is the delay before the first retry, doubled for every next one
+const retryBaseDelay = 200 * time.Millisecond
+
+// Retry calls fn until it succeeds, at most retries more times, and returns the last error.
+// The delays grow exponentially from baseDelay with full jitter, so the exporters scraping the
+// same node don't all retry at once after a hiccup.
+func Retry(retries int, baseDelay time.Duration, fn func() error) error {
+	err := fn()
+	for attempt := 0; err != nil && attempt < retries; attempt++ {
+		time.Sleep(Backoff(attempt, baseDelay))
+		err = fn()
+	}
+	return err
+}
+
+// Backoff returns a random delay between 0 and baseDelay * 2^attempt
+func Backoff(attempt int, baseDelay time.Duration) time.Duration {
+	maxDelay := baseDelay << attempt
+	if maxDelay <= 0 {
+		// overflow after too many attempts
+		maxDelay = baseDelay
+	}
+	return time.Duration(rand.Int63n(int64(maxDelay) + 1))
+}
@@ -2,28 +2,52 @@ package exporter
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/singleflight"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"math"
+	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type ServiceConfig struct {
 	ConfigPath string
+	Chain      string
 
 	Denom         string
 	ListenAddress string
 	NodeAddress   string
 	TendermintRPC string // needed to get upgrade info
+	AvgBlockTime  time.Duration
+	RPCRetries    int
+	UpgradeNames  []string
 	LogLevel      string
 	JSONOutput    bool
 	Limit         uint64
 
+	// permissions of the socket when ListenAddress is a unix socket
+	ListenSocketMode string
+
+	GrpcTLS                bool
+	GrpcInsecureSkipVerify bool
+	MaxConcurrency         int
+
 	Prefix                    string
 	AccountPrefix             string
 	AccountPubkeyPrefix       string
@@ -36,8 +60,10 @@ type ServiceConfig struct {
 
 	ChainID          string
 	ConstLabels      map[string]string
+	ExtraLabels      map[string]string
 	DenomCoefficient float64
 	DenomExponent    uint64
+	PowerReduction   uint64
 
 	// SingleReq bundle up multiple requests into a single /metrics
 	SingleReq  bool
@@ -50,10 +76,55 @@ type ServiceConfig struct {
 	TokenPrice bool
 	PropV1     bool
 	Votes      bool
+
+	SelfDelegation      bool
+	ValidatorTopN       int
+	RankMin             int
+	RankMax             int
+	CommissionBps       bool
+	CommissionPrecision int
+	UnbondedMissed      bool
+	SharesWithoutDenom  bool
+	StatusNameLabel     bool
+	UnbondingEntries    bool
+	TokensHistogram     bool
+	DelegationSizes     bool
+	OpenMetrics         bool
+	MinGasPrices        string
+	FeeMarket           string
+	OracleModule        string
+	AuthzGranter        string
+	GroupID             uint64
+	Epochs              bool
+	Wasm                bool
+	RecentBlocks        int
+
+	VestingAddresses []string
+
+	RawStorePath string
+	RawStoreKey  string
+	RawStoreInt  bool
+
+	TotalDelegators        bool
+	TotalDelegatorsRefresh time.Duration
+
+	PriceAPIURL string
+	PriceTTL    time.Duration
+
+	ValidatorsCacheTTL   time.Duration
+	PrewarmCache         bool
+	FallbackQueryTimeout time.Duration
+
+	CircuitBreakerFailures int
+	CircuitBreakerCooldown time.Duration
+
+	DisabledMetrics []string
+	InstanceName    string
+	SnapshotPath    string
 }
 
 type Service struct {
-	GrpcConn *grpc.ClientConn
+	GrpcConn ClientConn
 	//	TmRPC      *tmrpc.HTTP
 	Wallets    []string
 	Validators []string
@@ -63,6 +134,79 @@ type Service struct {
 	Params     bool
 	Config     *ServiceConfig
 	Log        zerolog.Logger
+
+	// results of the queries of the request served by this copy of the service, nil outside CircuitBreakerHandler
+	queries *scrapeQueries
+
+	// kept between the scrapes, and shared by the copies of the service serving each endpoint
+	*state
+}
+
+type state struct {
+	// denom traces never change once created, so they are only resolved once
+	denomTraces      map[string]transfertypes.DenomTrace
+	denomTracesMutex sync.Mutex
+
+	// ranks of the validators in the previous /metrics/validators scrape, to compute the rank changes
+	validatorRanks      map[string]int
+	validatorRanksMutex sync.Mutex
+
+	// when the bonded validators were first seen bonded, to compute how long they have been in the set
+	bondedSince      map[string]time.Time
+	bondedSinceMutex sync.Mutex
+
+	// missed blocks counters of the validators in the previous scrape, to detect the signing window resets
+	missedBlocks      map[string]*missedBlocks
+	missedBlocksMutex sync.Mutex
+
+	// warns once about using 1 instead of a zero denom coefficient
+	zeroCoefficientWarning sync.Once
+
+	// jailed status of the validators in the previous scrape, to count the unjail events
+	jailStates      map[string]*jailState
+	jailStatesMutex sync.Mutex
+
+	// delegators of the validators of /metrics/delegator in the previous scrape, to compute their changes
+	delegatorCounts      map[string]int
+	delegatorCountsMutex sync.Mutex
+
+	// tokens of the validators in the previous scrape and their accumulated changes
+	stakeFlows      map[string]*stakeFlow
+	stakeFlowsMutex sync.Mutex
+
+	// unique delegators of the chain, counted at most every --total-delegators-refresh
+	totalDelegators      totalDelegators
+	totalDelegatorsMutex sync.Mutex
+
+	// USD price of the token from --price-api-url
+	price      cachedPrice
+	priceMutex sync.Mutex
+
+	// validators set of /metrics/validators, warmed is set once PrewarmCache filled it
+	validatorsCache      validatorsCache
+	validatorsCacheMutex sync.Mutex
+	warmed               atomic.Bool
+
+	// failed gRPC queries by module since the exporter started
+	queryErrors      map[string]float64
+	queryErrorsMutex sync.Mutex
+
+	circuitBreaker CircuitBreaker
+
+	// scrapes in flight, shared with the concurrent identical ones by CoalesceHandler
+	scrapes singleflight.Group
+
+	// gRPC queries by endpoint since the exporter started
+	grpcQueries      map[string]float64
+	grpcQueriesMutex sync.Mutex
+
+	// bounds the gRPC queries in flight, nil if unbounded
+	querySemaphore chan struct{}
+}
+
+// NewService returns a service without connection, Connect has to be called before serving
+func NewService() *Service {
+	return &Service{state: &state{}}
 }
 
 func (s *Service) SetChainID(config *ServiceConfig) {
@@ -72,7 +216,7 @@ func (s *Service) SetChainID(config *ServiceConfig) {
 		&tmservice.GetNodeInfoRequest{},
 	)
 	if err != nil {
-		s.Log.Fatal().Err(err).Msg("Could not query Tendermint status")
+		s.Log.Fatal().Err(err).Str("--node", config.NodeAddress).Msg("Could not query Tendermint status, is the gRPC endpoint reachable?")
 	}
 
 	s.Log.Info().Str("network", response.GetDefaultNodeInfo().Network).Msg("Got network status from Tendermint")
@@ -80,6 +224,22 @@ func (s *Service) SetChainID(config *ServiceConfig) {
 	config.ConstLabels = map[string]string{
 		"chain_id": config.ChainID,
 	}
+	for name, value := range config.ExtraLabels {
+		config.ConstLabels[name] = value
+	}
+}
+
+// CheckRPC fails fast if the RPC endpoint is unreachable while a metric only served from it was enabled,
+// and warns otherwise, as the upgrade estimate, the consensus state and the validator set won't be served
+func (s *Service) CheckRPC(config *ServiceConfig) {
+	if _, err := NewChainStatus(config); err != nil {
+		if config.Upgrades || config.RawStorePath != "" {
+			s.Log.Fatal().Err(err).Str("--tendermint-rpc", config.TendermintRPC).Msg("Could not reach the RPC endpoint")
+		}
+		s.Log.Warn().Err(err).Str("--tendermint-rpc", config.TendermintRPC).Msg("Could not reach the RPC endpoint, the metrics depending on it won't be served")
+		return
+	}
+	s.Log.Info().Str("--tendermint-rpc", config.TendermintRPC).Msg("Reached the RPC endpoint")
 }
 func (s *Service) Connect(config *ServiceConfig) error {
 	var err error
@@ -90,15 +250,30 @@ func (s *Service) Connect(config *ServiceConfig) error {
 			return err
 		}
 	*/
-	s.GrpcConn, err = grpc.Dial(
+	if config.MaxConcurrency > 0 {
+		s.querySemaphore = make(chan struct{}, config.MaxConcurrency)
+	}
+
+	transportCredentials := insecure.NewCredentials()
+	if config.GrpcTLS {
+		transportCredentials = credentials.NewTLS(&tls.Config{
+			// only meant for self-signed nodes on private infra, this allows anyone in between to read and forge the responses
+			InsecureSkipVerify: config.GrpcInsecureSkipVerify,
+		})
+	}
+
+	conn, err := grpc.Dial(
 		config.NodeAddress,
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithUserAgent(config.UserAgent()),
+		grpc.WithChainUnaryInterceptor(s.queryErrorsInterceptor, config.instanceInterceptor, s.concurrencyInterceptor))
 
 	if err != nil {
 		//log.Fatal().Err(err).Msg("Could not connect to gRPC node")
 		return err
 	}
 
+	s.GrpcConn = conn
 	return nil
 }
 func (s *Service) Close() error {
@@ -106,6 +281,90 @@ func (s *Service) Close() error {
 	return err
 }
 
+// PowerReductionInt returns --power-reduction as the int the SDK computes the consensus power with
+func (config *ServiceConfig) PowerReductionInt() sdk.Int {
+	return sdk.NewIntFromUint64(config.PowerReduction)
+}
+
+// sharesLabelNames returns the labels of the delegator shares gauges, without denom with --shares-without-denom
+func (config *ServiceConfig) sharesLabelNames() []string {
+	if config.SharesWithoutDenom {
+		return []string{"address", "moniker"}
+	}
+	return []string{"address", "moniker", "denom"}
+}
+
+func (config *ServiceConfig) sharesLabels(address, moniker string) prometheus.Labels {
+	labels := prometheus.Labels{"address": address, "moniker": moniker}
+	if !config.SharesWithoutDenom {
+		labels["denom"] = config.Denom
+	}
+	return labels
+}
+
+// statusLabelNames returns the labels of the status gauge, with the name of the status with --status-name-label
+func (config *ServiceConfig) statusLabelNames() []string {
+	if config.StatusNameLabel {
+		return []string{"address", "moniker", "status_name"}
+	}
+	return []string{"address", "moniker"}
+}
+
+func (config *ServiceConfig) statusLabels(validator stakingtypes.Validator) prometheus.Labels {
+	labels := prometheus.Labels{"address": validator.OperatorAddress, "moniker": validator.Description.Moniker}
+	if config.StatusNameLabel {
+		labels["status_name"] = validator.Status.String()
+	}
+	return labels
+}
+
+// UserAgent returns the user agent sent to the node, so node operators can tell which exporter the queries come from
+func (config *ServiceConfig) UserAgent() string {
+	if config.InstanceName == "" {
+		return "cosmos-exporter"
+	}
+	return "cosmos-exporter/" + config.InstanceName
+}
+
+// instanceInterceptor adds the instance name to the metadata of every query, for the nodes behind proxies rewriting the user agent
+func (config *ServiceConfig) instanceInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if config.InstanceName != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-exporter-instance", config.InstanceName)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// concurrencyInterceptor makes the queries wait for a free slot when --max-concurrency is set,
+// so the number of queries in flight to the node stays bounded however many goroutines a scrape spawns
+func (s *Service) concurrencyInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if s.querySemaphore != nil {
+		select {
+		case s.querySemaphore <- struct{}{}:
+			defer func() { <-s.querySemaphore }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// ServeMetrics writes out the metrics gathered in the registry, using the same exposition options for every handler
+// the `metrics` query param (e.g. ?metrics=cosmos_validators_jailed,cosmos_validators_missed_blocks) only serves the listed metrics
+func (s *Service) ServeMetrics(w http.ResponseWriter, r *http.Request, registry prometheus.Gatherer) {
+	registry = prometheus.Gatherers{registry, s.exporterRegistry()}
+	if allowed := MetricsAllowlist(r); len(allowed) > 0 {
+		registry = FilterGatherer(registry, allowed)
+	}
+	if s.Config.SnapshotPath != "" {
+		registry = s.SnapshotGatherer(registry, r)
+	}
+
+	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: s.Config.OpenMetrics,
+	})
+	h.ServeHTTP(w, r)
+}
+
 func (s *Service) SetDenom(config *ServiceConfig) {
 	// if --denom and (--denom-coefficient or --denom-exponent) are provided, use them
 	// instead of fetching them via gRPC. Can be useful for networks like osmosis.
@@ -199,14 +458,29 @@ func (s *Service) GetLatestBlock() (float64, error) {
 func (config *ServiceConfig) SetCommonParameters(cmd *cobra.Command) {
 
 	cmd.PersistentFlags().StringVar(&config.ConfigPath, "config", "", "Config file path")
+	cmd.PersistentFlags().StringVar(&config.Chain, "chain", "", "section of the chains of the config file to use")
+	cmd.PersistentFlags().StringToStringVar(&config.ExtraLabels, "const-labels", nil, "labels added to every metric along with chain_id (e.g. network=mainnet)")
 	cmd.PersistentFlags().StringVar(&config.Denom, "denom", "", "Cosmos coin denom")
 	cmd.PersistentFlags().Float64Var(&config.DenomCoefficient, "denom-coefficient", 1, "Denom coefficient")
 	cmd.PersistentFlags().Uint64Var(&config.DenomExponent, "denom-exponent", 0, "Denom exponent")
-	cmd.PersistentFlags().StringVar(&config.ListenAddress, "listen-address", ":9300", "The address this exporter would listen on")
-	cmd.PersistentFlags().StringVar(&config.NodeAddress, "node", "localhost:9090", "GRPC node address")
+	cmd.PersistentFlags().Uint64Var(&config.PowerReduction, "power-reduction", sdk.DefaultPowerReduction.Uint64(), "tokens per unit of consensus power, 1000000000000000000 on most 18 decimals chains")
+	cmd.PersistentFlags().StringVar(&config.ListenAddress, "listen-address", ":9300", "The address this exporter would listen on, or unix:/path/to/socket")
+	cmd.PersistentFlags().StringVar(&config.ListenSocketMode, "listen-socket-mode", "0660", "permissions of the unix socket of --listen-address")
+	cmd.PersistentFlags().StringVar(&config.NodeAddress, "node", "localhost:9090", "gRPC endpoint of the node, used by all the queries")
+	cmd.PersistentFlags().BoolVar(&config.GrpcTLS, "grpc-tls", false, "connect to the gRPC node over TLS")
+	cmd.PersistentFlags().BoolVar(&config.GrpcInsecureSkipVerify, "grpc-insecure-skip-verify", false, "INSECURE: don't verify the gRPC node TLS certificate, for self-signed nodes only")
 	cmd.PersistentFlags().StringVar(&config.LogLevel, "log-level", "info", "Logging level")
 	cmd.PersistentFlags().Uint64Var(&config.Limit, "limit", 1000, "Pagination limit for gRPC requests")
-	cmd.PersistentFlags().StringVar(&config.TendermintRPC, "tendermint-rpc", "http://localhost:26657", "Tendermint RPC address")
+	cmd.PersistentFlags().IntVar(&config.MaxConcurrency, "max-concurrency", 0, "Maximum number of gRPC requests in flight to the node, 0 for no limit")
+	cmd.PersistentFlags().DurationVar(&config.ValidatorsCacheTTL, "validators-cache-ttl", 0, "how long the validators set queried by /metrics/validators is cached, 0 to query it on every scrape")
+	cmd.PersistentFlags().BoolVar(&config.PrewarmCache, "prewarm-cache", false, "fill the validators cache at startup, /ready answers 503 until it's done")
+	cmd.PersistentFlags().DurationVar(&config.FallbackQueryTimeout, "fallback-query-timeout", 2*time.Second, "timeout of each signing info queried one by one in /metrics/validators when missing from the bulk query, 0 for none")
+	cmd.PersistentFlags().IntVar(&config.CircuitBreakerFailures, "circuit-breaker-failures", 0, "consecutive failed scrapes after which the scrapes stop querying the node for --circuit-breaker-cooldown, 0 to always query it")
+	cmd.PersistentFlags().DurationVar(&config.CircuitBreakerCooldown, "circuit-breaker-cooldown", 30*time.Second, "how long the scrapes don't query the node once the circuit is open")
+	cmd.PersistentFlags().StringVar(&config.TendermintRPC, "tendermint-rpc", "http://localhost:26657", "CometBFT RPC endpoint of the node, used for the chain status, consensus state and validator set")
+	cmd.PersistentFlags().StringSliceVar(&config.UpgradeNames, "upgrade-names", nil, "names of the upgrades to report the applied height of in /metrics/upgrade")
+	cmd.PersistentFlags().DurationVar(&config.AvgBlockTime, "avg-block-time", 0, "Average block time used to estimate the upgrade time, instead of sampling it from the node")
+	cmd.PersistentFlags().IntVar(&config.RPCRetries, "rpc-retries", 2, "times a failed CometBFT RPC status call is retried, with a jittered exponential backoff")
 	cmd.PersistentFlags().BoolVar(&config.JSONOutput, "json", false, "Output logs as JSON")
 
 	// some networks, like Iris, have the different prefixes for address, validator and consensus node
@@ -223,10 +497,42 @@ func (config *ServiceConfig) SetCommonParameters(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVar(&config.Proposals, "proposals", false, "serve active proposal info in the single call to /metrics")
 	cmd.PersistentFlags().BoolVar(&config.Params, "params", false, "serve chain params info in the single call to /metrics")
 	cmd.PersistentFlags().BoolVar(&config.TokenPrice, "price", true, "fetch token price")
+	cmd.PersistentFlags().StringVar(&config.PriceAPIURL, "price-api-url", "", "URL returning the USD price of the token in the CoinGecko simple price format, served in /metrics/general")
+	cmd.PersistentFlags().DurationVar(&config.PriceTTL, "price-ttl", 5*time.Minute, "how long the price from --price-api-url is cached")
 	cmd.PersistentFlags().StringSliceVar(&config.Wallets, "wallets", nil, "serve info about passed wallets")
 	cmd.PersistentFlags().StringSliceVar(&config.Validators, "validators", nil, "serve info about passed validators")
 	cmd.PersistentFlags().BoolVar(&config.PropV1, "propv1", false, "use PropV1 instead of PropV1Beta calls")
 	cmd.PersistentFlags().BoolVar(&config.Votes, "votes", false, "get validator votes on active proposals")
+	cmd.PersistentFlags().IntVar(&config.ValidatorTopN, "validators-top-n", 0, "only serve the first N validators by delegator shares in /metrics/validators, plus the ones passed with --validators, 0 for all")
+	cmd.PersistentFlags().IntVar(&config.RankMin, "rank-min", 0, "only serve the ranks and voting power of the validators ranked from this one in /metrics/validators, 0 for the first")
+	cmd.PersistentFlags().IntVar(&config.RankMax, "rank-max", 0, "only serve the ranks and voting power of the validators ranked up to this one in /metrics/validators, 0 for the last")
+	cmd.PersistentFlags().BoolVar(&config.SelfDelegation, "self-delegation", false, "query the self delegation of every validator in /metrics/validators")
+	cmd.PersistentFlags().BoolVar(&config.CommissionBps, "commission-bps", false, "also serve the validators commission in basis points in /metrics/validators")
+	cmd.PersistentFlags().IntVar(&config.CommissionPrecision, "commission-precision", 0, "round the validators commission to this number of decimals, 0 for the full precision")
+	cmd.PersistentFlags().BoolVar(&config.UnbondedMissed, "unbonded-missed-blocks", false, "also serve the missed blocks of the jailed and unbonding validators in /metrics/validators")
+	cmd.PersistentFlags().BoolVar(&config.SharesWithoutDenom, "shares-without-denom", false, "drop the denom label of the delegator shares gauges, as shares aren't denominated in the token")
+	cmd.PersistentFlags().BoolVar(&config.StatusNameLabel, "status-name-label", false, "add the name of the status, like BOND_STATUS_BONDED, as a status_name label of cosmos_validators_status")
+	cmd.PersistentFlags().BoolVar(&config.UnbondingEntries, "unbonding-entries", false, "query the pending unbonding delegations of every validator in /metrics/validators")
+	cmd.PersistentFlags().BoolVar(&config.DelegationSizes, "delegation-sizes", false, "also serve the distribution of the delegations of the validators in /metrics/delegator, expensive for the validators with a lot of delegators")
+	cmd.PersistentFlags().BoolVar(&config.TokensHistogram, "tokens-histogram", false, "serve the validators tokens as a single histogram instead of a gauge per validator in /metrics/validators")
+	cmd.PersistentFlags().BoolVar(&config.OpenMetrics, "openmetrics", false, "serve metrics in the OpenMetrics format when the scraper asks for it")
+	cmd.PersistentFlags().StringVar(&config.MinGasPrices, "min-gas-prices", "", "static minimum gas prices to serve in /metrics/gas instead of asking the node (e.g. 0.0025uatom)")
+	cmd.PersistentFlags().StringVar(&config.FeeMarket, "feemarket", "", "fee market module of the chain, served in /metrics/feemarket (skip, osmosis or evmos)")
+	cmd.PersistentFlags().StringVar(&config.OracleModule, "oracle-module", "", "oracle module of the chain, whose validators miss counters are served in /metrics/oracle (kujira, terra or sei)")
+	cmd.PersistentFlags().StringVar(&config.AuthzGranter, "authz-granter", "", "serve the expiration of the authz grants given by this address in /metrics/authz")
+	cmd.PersistentFlags().StringVar(&config.InstanceName, "instance-name", "", "name of this exporter, sent to the node in the user agent and the x-exporter-instance metadata")
+	cmd.PersistentFlags().StringVar(&config.SnapshotPath, "snapshot-path", "", "file to write the metrics of the last scrape to, for debugging")
+	cmd.PersistentFlags().Uint64Var(&config.GroupID, "group-id", 0, "serve the members and policy balances of this x/group group in /metrics/group")
+	cmd.PersistentFlags().BoolVar(&config.Epochs, "epochs", false, "serve the x/epochs module epochs in /metrics/epochs, for osmosis based chains")
+	cmd.PersistentFlags().StringSliceVar(&config.VestingAddresses, "vesting-addresses", nil, "serve the vesting schedule of these vesting accounts in /metrics/vesting")
+	cmd.PersistentFlags().StringVar(&config.RawStorePath, "raw-store-path", "", "ABCI query path of the store key served in /metrics/raw-store (e.g. /store/bank/key)")
+	cmd.PersistentFlags().StringVar(&config.RawStoreKey, "raw-store-key", "", "hex encoded store key queried at --raw-store-path")
+	cmd.PersistentFlags().BoolVar(&config.RawStoreInt, "raw-store-int", false, "also serve the value of --raw-store-key parsed as an integer")
+	cmd.PersistentFlags().BoolVar(&config.Wasm, "wasm", false, "serve the CosmWasm code and contract counts in /metrics/wasm, for CosmWasm chains")
+	cmd.PersistentFlags().IntVar(&config.RecentBlocks, "recent-blocks", 0, fmt.Sprintf("serve the signatures of the validators in the last N blocks in /metrics/recent-signatures, at most %d", MaxRecentBlocks))
+	cmd.PersistentFlags().BoolVar(&config.TotalDelegators, "total-delegators", false, "serve the unique delegators of the chain in /metrics/total-delegators, expensive on large chains")
+	cmd.PersistentFlags().DurationVar(&config.TotalDelegatorsRefresh, "total-delegators-refresh", time.Hour, "how often the total delegators are counted again")
+	cmd.PersistentFlags().StringSliceVar(&config.DisabledMetrics, "disabled-metrics", nil, "names of the metrics not to serve, e.g. cosmos_validators_tokens")
 }
 func (config *ServiceConfig) LogConfig(event *zerolog.Event) *zerolog.Event {
 	return event.
@@ -236,22 +542,69 @@ func (config *ServiceConfig) LogConfig(event *zerolog.Event) *zerolog.Event {
 		Str("--bech-validator-pubkey-prefix", config.ValidatorPubkeyPrefix).
 		Str("--bech-consensus-node-prefix", config.ConsensusNodePrefix).
 		Str("--bech-consensus-node-pubkey-prefix", config.ConsensusNodePubkeyPrefix).
+		Str("--chain", config.Chain).
+		Str("--const-labels", fmt.Sprintf("%v", config.ExtraLabels)).
 		Str("--denom", config.Denom).
 		Str("--denom-cofficient", fmt.Sprintf("%f", config.DenomCoefficient)).
 		Str("--denom-exponent", fmt.Sprintf("%d", config.DenomExponent)).
+		Uint64("--power-reduction", config.PowerReduction).
 		Str("--listen-address", config.ListenAddress).
+		Str("--listen-socket-mode", config.ListenSocketMode).
 		Str("--node", config.NodeAddress).
+		Bool("--grpc-tls", config.GrpcTLS).
+		Bool("--grpc-insecure-skip-verify", config.GrpcInsecureSkipVerify).
 		Str("--log-level", config.LogLevel).
+		Int("--max-concurrency", config.MaxConcurrency).
+		Dur("--validators-cache-ttl", config.ValidatorsCacheTTL).
+		Bool("--prewarm-cache", config.PrewarmCache).
+		Dur("--fallback-query-timeout", config.FallbackQueryTimeout).
+		Int("--circuit-breaker-failures", config.CircuitBreakerFailures).
+		Dur("--circuit-breaker-cooldown", config.CircuitBreakerCooldown).
 		Bool("--single", config.SingleReq).
 		Str("--tendermint-rpc", config.TendermintRPC).
+		Dur("--avg-block-time", config.AvgBlockTime).
+		Int("--rpc-retries", config.RPCRetries).
+		Str("--upgrade-names", strings.Join(config.UpgradeNames[:], ",")).
 		Str("--wallets", strings.Join(config.Wallets[:], ",")).
 		Str("--validators", strings.Join(config.Validators[:], ",")).
 		Bool("--proposals", config.Proposals).
 		Bool("--params", config.Params).
 		Bool("--upgrades", config.Upgrades).
 		Bool("--price", config.TokenPrice).
+		Str("--price-api-url", config.PriceAPIURL).
+		Dur("--price-ttl", config.PriceTTL).
 		Bool("--propv1", config.PropV1).
-		Bool("--votes", config.Votes)
+		Bool("--votes", config.Votes).
+		Int("--validators-top-n", config.ValidatorTopN).
+		Int("--rank-min", config.RankMin).
+		Int("--rank-max", config.RankMax).
+		Bool("--self-delegation", config.SelfDelegation).
+		Bool("--commission-bps", config.CommissionBps).
+		Int("--commission-precision", config.CommissionPrecision).
+		Bool("--unbonded-missed-blocks", config.UnbondedMissed).
+		Bool("--shares-without-denom", config.SharesWithoutDenom).
+		Bool("--status-name-label", config.StatusNameLabel).
+		Bool("--unbonding-entries", config.UnbondingEntries).
+		Bool("--tokens-histogram", config.TokensHistogram).
+		Bool("--delegation-sizes", config.DelegationSizes).
+		Bool("--openmetrics", config.OpenMetrics).
+		Str("--min-gas-prices", config.MinGasPrices).
+		Str("--feemarket", config.FeeMarket).
+		Str("--oracle-module", config.OracleModule).
+		Str("--authz-granter", config.AuthzGranter).
+		Uint64("--group-id", config.GroupID).
+		Bool("--epochs", config.Epochs).
+		Bool("--wasm", config.Wasm).
+		Int("--recent-blocks", config.RecentBlocks).
+		Str("--vesting-addresses", strings.Join(config.VestingAddresses[:], ",")).
+		Str("--raw-store-path", config.RawStorePath).
+		Str("--raw-store-key", config.RawStoreKey).
+		Bool("--raw-store-int", config.RawStoreInt).
+		Bool("--total-delegators", config.TotalDelegators).
+		Dur("--total-delegators-refresh", config.TotalDelegatorsRefresh).
+		Str("--disabled-metrics", strings.Join(config.DisabledMetrics[:], ",")).
+		Str("--instance-name", config.InstanceName).
+		Str("--snapshot-path", config.SnapshotPath)
 }
 
 func (config *ServiceConfig) SetBechPrefixes(cmd *cobra.Command) {
@@ -0,0 +1,59 @@
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// SnapshotGatherer writes the metrics of every successful gathering to --snapshot-path,
+// so we can see exactly what the exporter served when a user reports wrong values
+func (s *Service) SnapshotGatherer(gatherer prometheus.Gatherer, r *http.Request) prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := gatherer.Gather()
+		if err != nil {
+			return families, err
+		}
+
+		if err := writeSnapshot(s.Config.SnapshotPath, r.URL.RequestURI(), families); err != nil {
+			s.Log.Error().
+				Str("request-id", RequestID(r)).
+				Str("path", s.Config.SnapshotPath).
+				Err(err).
+				Msg("Could not write metrics snapshot")
+		}
+		return families, nil
+	})
+}
+
+// writeSnapshot writes the metrics in the text exposition format to a temporary file renamed over the snapshot,
+// so a reader never sees a partly written one
+func writeSnapshot(path string, endpoint string, families []*dto.MetricFamily) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := fmt.Fprintf(file, "# endpoint: %s\n# time: %s\n", endpoint, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		file.Close()
+		return err
+	}
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(file, family); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
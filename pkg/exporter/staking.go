@@ -0,0 +1,135 @@
+package exporter
+
+import (
+	"context"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type StakingMetrics struct {
+	maxValidatorsGauge     prometheus.Gauge
+	maxEntriesGauge        prometheus.Gauge
+	unbondingTimeGauge     prometheus.Gauge
+	historicalEntriesGauge prometheus.Gauge
+	bondDenomGauge         *prometheus.GaugeVec
+}
+
+func NewStakingMetrics(reg prometheus.Registerer, config *ServiceConfig) *StakingMetrics {
+	m := &StakingMetrics{
+		maxValidatorsGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_staking_max_validators",
+				Help:        "Maximum number of validators in the active set",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		maxEntriesGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_staking_max_entries",
+				Help:        "Maximum number of unbonding or redelegation entries per delegator and validator pair",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		unbondingTimeGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_staking_unbonding_time",
+				Help:        "Unbonding time, in seconds",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		historicalEntriesGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_staking_historical_entries",
+				Help:        "Number of historical entries kept by the staking module",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		bondDenomGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_staking_bond_denom",
+				Help:        "Denom used for staking, always 1",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"denom"},
+		),
+	}
+
+	reg.MustRegister(m.maxValidatorsGauge)
+	reg.MustRegister(m.maxEntriesGauge)
+	reg.MustRegister(m.unbondingTimeGauge)
+	reg.MustRegister(m.historicalEntriesGauge)
+	reg.MustRegister(m.bondDenomGauge)
+
+	return m
+}
+func GetStakingMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *StakingMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying global staking params")
+		queryStart := time.Now()
+
+		params, err := s.GetStakingParams()
+		if err != nil {
+			sublogger.Error().
+				Err(err).
+				Msg("Could not get global staking params")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying global staking params")
+
+		metrics.maxValidatorsGauge.Set(float64(params.MaxValidators))
+		metrics.maxEntriesGauge.Set(float64(params.MaxEntries))
+		metrics.unbondingTimeGauge.Set(params.UnbondingTime.Seconds())
+		metrics.historicalEntriesGauge.Set(float64(params.HistoricalEntries))
+		metrics.bondDenomGauge.With(prometheus.Labels{
+			"denom": params.BondDenom,
+		}).Set(1)
+	}()
+
+}
+
+// GetStakingParams returns the global staking params
+func (s *Service) GetStakingParams() (stakingtypes.Params, error) {
+	stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
+	paramsResponse, err := stakingClient.Params(
+		context.Background(),
+		&stakingtypes.QueryParamsRequest{},
+	)
+	if err != nil {
+		return stakingtypes.Params{}, err
+	}
+	return paramsResponse.Params, nil
+}
+func (s *Service) StakingHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/staking")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	stakingMetrics := NewStakingMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetStakingMetrics(&wg, &sublogger, stakingMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/staking").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
@@ -0,0 +1,160 @@
+package exporter
+
+import (
+	"context"
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type TotalDelegatorsMetrics struct {
+	totalDelegatorsGauge prometheus.Gauge
+	updatedTimeGauge     prometheus.Gauge
+}
+
+// totalDelegators is the last count of the unique delegators, refreshed in the background
+// as paging through the delegations of every validator takes a while on large chains
+type totalDelegators struct {
+	count      int
+	updated    time.Time
+	refreshing bool
+}
+
+func NewTotalDelegatorsMetrics(reg prometheus.Registerer, config *ServiceConfig) *TotalDelegatorsMetrics {
+	m := &TotalDelegatorsMetrics{
+		totalDelegatorsGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_total_delegators",
+				Help:        "Unique delegator addresses across all the validators",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		updatedTimeGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_total_delegators_updated_time",
+				Help:        "Time cosmos_total_delegators was counted at, as unix timestamp",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+	}
+	reg.MustRegister(m.totalDelegatorsGauge)
+	reg.MustRegister(m.updatedTimeGauge)
+	return m
+}
+func GetTotalDelegatorsMetrics(sublogger *zerolog.Logger, metrics *TotalDelegatorsMetrics, s *Service, config *ServiceConfig) {
+	s.totalDelegatorsMutex.Lock()
+	defer s.totalDelegatorsMutex.Unlock()
+
+	if !s.totalDelegators.refreshing && time.Since(s.totalDelegators.updated) >= config.TotalDelegatorsRefresh {
+		s.totalDelegators.refreshing = true
+		sublogger.Debug().Msg("Started refreshing total delegators in the background")
+		go s.background().refreshTotalDelegators()
+	}
+
+	if s.totalDelegators.updated.IsZero() {
+		// not counted yet, don't report a misleading 0
+		return
+	}
+
+	metrics.totalDelegatorsGauge.Set(float64(s.totalDelegators.count))
+	metrics.updatedTimeGauge.Set(float64(s.totalDelegators.updated.Unix()))
+}
+
+func (s *Service) refreshTotalDelegators() {
+	queryStart := time.Now()
+
+	count, err := s.countDelegators()
+
+	s.totalDelegatorsMutex.Lock()
+	defer s.totalDelegatorsMutex.Unlock()
+	s.totalDelegators.refreshing = false
+
+	if err != nil {
+		s.Log.Error().Err(err).Msg("Could not count total delegators")
+		return
+	}
+
+	s.totalDelegators.count = count
+	s.totalDelegators.updated = time.Now()
+	s.Log.Debug().
+		Float64("request-time", time.Since(queryStart).Seconds()).
+		Int("totalDelegators", count).
+		Msg("Finished refreshing total delegators")
+}
+
+// countDelegators pages through the delegations of every validator, counting each delegator once
+func (s *Service) countDelegators() (int, error) {
+	stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
+
+	validators, err := Paginate(func(key []byte) ([]stakingtypes.Validator, []byte, error) {
+		validatorsResponse, err := stakingClient.Validators(
+			context.Background(),
+			&stakingtypes.QueryValidatorsRequest{
+				Pagination: &querytypes.PageRequest{
+					Key:   key,
+					Limit: s.Config.Limit,
+				},
+			},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		return validatorsResponse.Validators, validatorsResponse.Pagination.GetNextKey(), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	delegators := make(map[string]struct{})
+	for _, validator := range validators {
+		// only the delegator addresses are kept, not all the delegations of the chain
+		_, err := Paginate(func(key []byte) ([]struct{}, []byte, error) {
+			delegationsResponse, err := stakingClient.ValidatorDelegations(
+				context.Background(),
+				&stakingtypes.QueryValidatorDelegationsRequest{
+					ValidatorAddr: validator.OperatorAddress,
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: s.Config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			for _, delegation := range delegationsResponse.DelegationResponses {
+				delegators[delegation.Delegation.DelegatorAddress] = struct{}{}
+			}
+			return nil, delegationsResponse.Pagination.GetNextKey(), nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(delegators), nil
+}
+func (s *Service) TotalDelegatorsHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/total-delegators")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	totalDelegatorsMetrics := NewTotalDelegatorsMetrics(registry, s.Config)
+
+	GetTotalDelegatorsMetrics(&sublogger, totalDelegatorsMetrics, s, s.Config)
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/total-delegators").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
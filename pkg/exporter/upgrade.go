@@ -2,6 +2,8 @@ package exporter
 
 import (
 	"context"
+	"fmt"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
 	"github.com/rs/zerolog"
 	"net/http"
@@ -9,13 +11,22 @@ import (
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
+	govtypeV1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	msgSoftwareUpgradeTypeURL      = "/cosmos.upgrade.v1beta1.MsgSoftwareUpgrade"
+	softwareUpgradeProposalTypeURL = "/cosmos.upgrade.v1beta1.SoftwareUpgradeProposal"
+	msgExecLegacyContentTypeURL    = "/cosmos.gov.v1.MsgExecLegacyContent"
 )
 
 type UpgradeMetrics struct {
-	upgradePlanGauge *prometheus.GaugeVec
+	upgradePlanGauge       *prometheus.GaugeVec
+	currentHeightGauge     prometheus.Gauge
+	lastAppliedHeightGauge *prometheus.GaugeVec
+	proposedHeightGauge    *prometheus.GaugeVec
 }
 
 func NewUpgradeMetrics(reg prometheus.Registerer, config *ServiceConfig) *UpgradeMetrics {
@@ -28,8 +39,34 @@ func NewUpgradeMetrics(reg prometheus.Registerer, config *ServiceConfig) *Upgrad
 			},
 			[]string{"info", "name", "height", "estimated_time"},
 		),
+		currentHeightGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_upgrade_current_height",
+				Help:        "Latest block height the remaining height of the upgrade plan was computed from",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		lastAppliedHeightGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_upgrade_last_applied_height",
+				Help:        "Height the upgrade was applied at",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"name"},
+		),
+		proposedHeightGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_upgrade_proposed_height",
+				Help:        "Height of the upgrade scheduled by a voting period or passed gov proposal",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"name", "proposal_id"},
+		),
 	}
 	reg.MustRegister(m.upgradePlanGauge)
+	reg.MustRegister(m.currentHeightGauge)
+	reg.MustRegister(m.lastAppliedHeightGauge)
+	reg.MustRegister(m.proposedHeightGauge)
 	return m
 }
 func GetUpgradeMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *UpgradeMetrics, s *Service, config *ServiceConfig) {
@@ -55,6 +92,13 @@ func GetUpgradeMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *U
 			Float64("request-time", time.Since(queryStart).Seconds()).
 			Msg("Finished querying upgrade plan")
 
+		// the upgrade module can't list the applied plans, so only the known names are checked
+		appliedNames := config.UpgradeNames
+		if upgradeRes.Plan != nil {
+			appliedNames = append(appliedNames[:len(appliedNames):len(appliedNames)], upgradeRes.Plan.Name)
+		}
+		getAppliedPlanMetrics(wg, sublogger, metrics, s, appliedNames)
+
 		if upgradeRes.Plan == nil {
 			metrics.upgradePlanGauge.With(prometheus.Labels{
 				"info":           "None",
@@ -65,7 +109,7 @@ func GetUpgradeMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *U
 			return
 		}
 
-		cs, err := NewChainStatus(config)
+		cs, err := s.chainStatus()
 		if err != nil {
 			sublogger.Error().
 				Err(err).
@@ -73,6 +117,8 @@ func GetUpgradeMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *U
 			return
 		}
 
+		metrics.currentHeightGauge.Set(float64(cs.SyncInfo().LatestBlockHeight))
+
 		upgradeHeight := upgradeRes.Plan.Height
 		remainingHeight := upgradeHeight - cs.SyncInfo().LatestBlockHeight
 
@@ -86,7 +132,7 @@ func GetUpgradeMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *U
 			return
 		}
 
-		estimatedTime, err := cs.EstimateBlockTime(remainingHeight)
+		estimatedTime, err := EstimatedUpgradeTime(cs, remainingHeight)
 		if err != nil {
 			sublogger.Error().
 				Err(err).
@@ -97,19 +143,199 @@ func GetUpgradeMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *U
 			"info":           upgradeRes.Plan.Info,
 			"name":           upgradeRes.Plan.Name,
 			"height":         strconv.FormatInt(upgradeHeight, 10),
-			"estimated_time": estimatedTime.Local().Format(time.RFC1123),
+			"estimated_time": estimatedTime,
 		}).Set(float64(remainingHeight))
 	}()
 
+	getProposedUpgradeMetrics(wg, sublogger, metrics, s, config)
+}
+
+func getAppliedPlanMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *UpgradeMetrics, s *Service, names []string) {
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			sublogger.Debug().Str("name", name).Msg("Started querying applied plan")
+			queryStart := time.Now()
+
+			upgradeClient := upgradetypes.NewQueryClient(s.GrpcConn)
+			appliedRes, err := upgradeClient.AppliedPlan(
+				context.Background(),
+				&upgradetypes.QueryAppliedPlanRequest{Name: name},
+			)
+			if err != nil {
+				sublogger.Error().
+					Str("name", name).
+					Err(err).
+					Msg("Could not get applied plan")
+				return
+			}
+
+			sublogger.Debug().
+				Str("name", name).
+				Float64("request-time", time.Since(queryStart).Seconds()).
+				Msg("Finished querying applied plan")
+
+			// 0 when the plan wasn't applied (yet)
+			if appliedRes.Height == 0 {
+				return
+			}
+
+			metrics.lastAppliedHeightGauge.With(prometheus.Labels{
+				"name": name,
+			}).Set(float64(appliedRes.Height))
+		}(name)
+	}
+}
+
+// getProposedUpgradeMetrics exposes the upgrades scheduled via gov proposals, as CurrentPlan
+// is only populated once the proposal has passed
+func getProposedUpgradeMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *UpgradeMetrics, s *Service, config *ServiceConfig) {
+	if config.PropV1 {
+		for _, status := range []govtypeV1.ProposalStatus{govtypeV1.StatusVotingPeriod, govtypeV1.StatusPassed} {
+			wg.Add(1)
+			go func(status govtypeV1.ProposalStatus) {
+				defer wg.Done()
+				sublogger.Debug().Str("status", status.String()).Msg("Started querying v1 upgrade proposals")
+				queryStart := time.Now()
+
+				proposals, err := s.queryProposalsV1(status)
+				if err != nil {
+					sublogger.Error().
+						Str("status", status.String()).
+						Err(err).
+						Msg("Could not get upgrade proposals")
+					return
+				}
+
+				sublogger.Debug().
+					Str("status", status.String()).
+					Float64("request-time", time.Since(queryStart).Seconds()).
+					Msg("Finished querying v1 upgrade proposals")
+
+				for _, proposal := range proposals {
+					for _, message := range proposal.Messages {
+						plan, err := upgradePlanFromAny(message)
+						if err != nil {
+							sublogger.Error().
+								Str("proposal_id", fmt.Sprint(proposal.Id)).
+								Err(err).
+								Msg("Could not parse upgrade proposal message")
+							continue
+						}
+						if plan == nil {
+							continue
+						}
+
+						metrics.proposedHeightGauge.With(prometheus.Labels{
+							"name":        plan.Name,
+							"proposal_id": fmt.Sprint(proposal.Id),
+						}).Set(float64(plan.Height))
+					}
+				}
+			}(status)
+		}
+		return
+	}
+
+	for _, status := range []govtypes.ProposalStatus{govtypes.StatusVotingPeriod, govtypes.StatusPassed} {
+		wg.Add(1)
+		go func(status govtypes.ProposalStatus) {
+			defer wg.Done()
+			sublogger.Debug().Str("status", status.String()).Msg("Started querying v1beta1 upgrade proposals")
+			queryStart := time.Now()
+
+			proposals, err := s.queryProposals(status)
+			if err != nil {
+				sublogger.Error().
+					Str("status", status.String()).
+					Err(err).
+					Msg("Could not get upgrade proposals")
+				return
+			}
+
+			sublogger.Debug().
+				Str("status", status.String()).
+				Float64("request-time", time.Since(queryStart).Seconds()).
+				Msg("Finished querying v1beta1 upgrade proposals")
+
+			for _, proposal := range proposals {
+				plan, err := upgradePlanFromAny(proposal.Content)
+				if err != nil {
+					sublogger.Error().
+						Str("proposal_id", fmt.Sprint(proposal.ProposalId)).
+						Err(err).
+						Msg("Could not parse upgrade proposal content")
+					continue
+				}
+				if plan == nil {
+					continue
+				}
+
+				metrics.proposedHeightGauge.With(prometheus.Labels{
+					"name":        plan.Name,
+					"proposal_id": fmt.Sprint(proposal.ProposalId),
+				}).Set(float64(plan.Height))
+			}
+		}(status)
+	}
+}
+
+// upgradePlanFromAny returns the plan of a MsgSoftwareUpgrade or legacy SoftwareUpgradeProposal,
+// and nil for any other proposal message or content
+func upgradePlanFromAny(value *codectypes.Any) (*upgradetypes.Plan, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch value.TypeUrl {
+	case msgSoftwareUpgradeTypeURL:
+		var msg upgradetypes.MsgSoftwareUpgrade
+		if err := msg.Unmarshal(value.Value); err != nil {
+			return nil, err
+		}
+		return &msg.Plan, nil
+	case softwareUpgradeProposalTypeURL:
+		var content upgradetypes.SoftwareUpgradeProposal //nolint:staticcheck // still used by v1beta1 proposals
+		if err := content.Unmarshal(value.Value); err != nil {
+			return nil, err
+		}
+		return &content.Plan, nil
+	case msgExecLegacyContentTypeURL:
+		// legacy proposals submitted through gov v1 wrap their content
+		var msg govtypeV1.MsgExecLegacyContent
+		if err := msg.Unmarshal(value.Value); err != nil {
+			return nil, err
+		}
+		return upgradePlanFromAny(msg.Content)
+	default:
+		return nil, nil
+	}
+}
+
+// BlockTimeEstimator estimates when a block will be produced, implemented by ChainStatus
+type BlockTimeEstimator interface {
+	EstimateBlockTime(totalHeight int64) (time.Time, error)
+}
+
+// EstimatedUpgradeTime formats the estimated time of the upgrade for the estimated_time label,
+// leaving it empty when it can't be estimated rather than showing the zero time
+func EstimatedUpgradeTime(estimator BlockTimeEstimator, remainingHeight int64) (string, error) {
+	estimatedTime, err := estimator.EstimateBlockTime(remainingHeight)
+	if err != nil {
+		return "", err
+	}
+	return estimatedTime.Local().Format(time.RFC1123), nil
 }
 func (s *Service) UpgradeHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/upgrade")
 	requestStart := time.Now()
 
 	sublogger := s.Log.With().
-		Str("request-id", uuid.New().String()).
+		Str("request-id", RequestID(r)).
 		Logger()
 
-	registry := prometheus.NewRegistry()
+	registry := s.Config.NewRegistry()
 	upgradeMetrics := NewUpgradeMetrics(registry, s.Config)
 
 	var wg sync.WaitGroup
@@ -117,8 +343,7 @@ func (s *Service) UpgradeHandler(w http.ResponseWriter, r *http.Request) {
 
 	wg.Wait()
 
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	h.ServeHTTP(w, r)
+	s.ServeMetrics(w, r, registry)
 	sublogger.Info().
 		Str("method", "GET").
 		Str("endpoint", "/metrics/upgrade").
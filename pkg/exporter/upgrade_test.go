@@ -0,0 +1,33 @@
+package exporter_test
+
+import (
+	"errors"
+	"main/pkg/exporter"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type stubChainStatus struct {
+	estimated time.Time
+	err       error
+}
+
+func (cs stubChainStatus) EstimateBlockTime(totalHeight int64) (time.Time, error) {
+	return cs.estimated, cs.err
+}
+
+func TestEstimatedUpgradeTime(t *testing.T) {
+	estimated := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	estimatedTime, err := exporter.EstimatedUpgradeTime(stubChainStatus{estimated: estimated}, 100)
+	require.NoError(t, err)
+	require.Equal(t, estimated.Local().Format(time.RFC1123), estimatedTime)
+}
+
+func TestEstimatedUpgradeTimeError(t *testing.T) {
+	estimatedTime, err := exporter.EstimatedUpgradeTime(stubChainStatus{err: errors.New("no blocks to average")}, 100)
+	require.Error(t, err)
+	require.Empty(t, estimatedTime)
+}
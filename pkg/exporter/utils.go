@@ -3,14 +3,23 @@ package exporter
 import (
 	"context"
 	"fmt"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	tmrpc "github.com/tendermint/tendermint/rpc/client/http"
+	"math"
+	"math/big"
+	"strconv"
 	"time"
 
 	coretypes "github.com/tendermint/tendermint/rpc/core/types"
 )
 
 type ChainStatus struct {
+	client *tmrpc.HTTP
 	status *coretypes.ResultStatus
+	// set with --avg-block-time, as the block times sampled around upgrades are unreliable
+	avgBlockTime time.Duration
+	// results of the RPC queries of the scrape, for the circuit breaker
+	queries *scrapeQueries
 }
 
 func NewChainStatus(config *ServiceConfig) (ChainStatus, error) {
@@ -19,16 +28,31 @@ func NewChainStatus(config *ServiceConfig) (ChainStatus, error) {
 		return ChainStatus{}, err
 	}
 
-	status, err := client.Status(context.Background())
+	// a single flaky call would blank the metrics depending on the chain status, like the upgrade estimate
+	var status *coretypes.ResultStatus
+	err = Retry(config.RPCRetries, retryBaseDelay, func() error {
+		status, err = client.Status(context.Background())
+		return err
+	})
 	if err != nil {
 		return ChainStatus{}, err
 	}
 
 	return ChainStatus{
-		status: status,
+		client:       client,
+		status:       status,
+		avgBlockTime: config.AvgBlockTime,
 	}, nil
 }
 
+// chainStatus returns the chain status for the request served by s, counting its RPC queries for the circuit breaker
+func (s *Service) chainStatus() (ChainStatus, error) {
+	cs, err := NewChainStatus(s.Config)
+	s.queries.record(err)
+	cs.queries = s.queries
+	return cs, err
+}
+
 func (cs ChainStatus) SyncInfo() coretypes.SyncInfo {
 	return cs.status.SyncInfo
 }
@@ -38,6 +62,10 @@ func (cs ChainStatus) LatestBlockTime() time.Time {
 }
 
 func (cs ChainStatus) AvgBlockTIme() float64 {
+	if cs.avgBlockTime > 0 {
+		return cs.avgBlockTime.Seconds()
+	}
+
 	info := cs.SyncInfo()
 	diffHeight := float64(info.LatestBlockHeight - info.EarliestBlockHeight)
 	diffSeconds := float64(info.LatestBlockTime.Unix() - info.EarliestBlockTime.Unix())
@@ -61,3 +89,36 @@ func (cs ChainStatus) EstimateBlockTime(totalHeight int64) (time.Time, error) {
 
 	return estimated, nil
 }
+
+// DecToFloat64 converts a dec to the closest float64 going through its full 18 decimals string,
+// and errors out on decs missing from the response (e.g. params not existing on older chains) instead of returning 0
+func DecToFloat64(dec sdk.Dec) (float64, error) {
+	if dec.IsNil() {
+		return 0, fmt.Errorf("dec is nil")
+	}
+	return strconv.ParseFloat(dec.String(), 64)
+}
+
+// RoundDecToFloat64 rounds a dec to precision decimals before converting it with DecToFloat64, so 0.05 is served as
+// 0.05 and not as the float artifacts of its full 18 decimals, a precision of 0 keeps all of them
+func RoundDecToFloat64(dec sdk.Dec, precision int) (float64, error) {
+	if dec.IsNil() || precision <= 0 || precision >= sdk.Precision {
+		return DecToFloat64(dec)
+	}
+	multiplier := int64(math.Pow10(precision))
+	return DecToFloat64(sdk.NewDecFromIntWithPrec(dec.MulInt64(multiplier).RoundInt(), int64(precision)))
+}
+
+// IntToFloat64 divides an amount in base units by the denom coefficient exactly and rounds it once, so the amounts
+// of the 18 decimals chains aren't mangled by rounding them to float64 before dividing, and errors out on nil ints
+func IntToFloat64(amount sdk.Int, coefficient float64) (float64, error) {
+	if amount.IsNil() {
+		return 0, fmt.Errorf("int is nil")
+	}
+	divisor := new(big.Rat)
+	if divisor.SetFloat64(coefficient) == nil || divisor.Sign() == 0 {
+		return 0, fmt.Errorf("invalid denom coefficient %v", coefficient)
+	}
+	value, _ := new(big.Rat).Quo(new(big.Rat).SetInt(amount.BigInt()), divisor).Float64()
+	return value, nil
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,9 +18,7 @@ import (
 	querytypes "github.com/cosmos/cosmos-sdk/types/query"
 	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
-	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type ValidatorMetrics struct {
@@ -34,6 +33,7 @@ type ValidatorExtendedMetrics struct {
 	delegationsGauge   *prometheus.GaugeVec
 	commissionGauge    *prometheus.GaugeVec
 	rewardsGauge       *prometheus.GaugeVec
+	commissionShare    *prometheus.GaugeVec
 	unbondingsGauge    *prometheus.GaugeVec
 	redelegationsGauge *prometheus.GaugeVec
 
@@ -59,7 +59,7 @@ func NewValidatorMetrics(reg prometheus.Registerer, config *ServiceConfig) *Vali
 				Help:        "Delegators shares of the Cosmos-based blockchain validator",
 				ConstLabels: config.ConstLabels,
 			},
-			[]string{"address", "moniker", "denom"},
+			config.sharesLabelNames(),
 		),
 
 		commissionRateGauge: prometheus.NewGaugeVec(
@@ -136,6 +136,14 @@ func NewValidatorExtendedMetrics(reg prometheus.Registerer, config *ServiceConfi
 			},
 			[]string{"address", "moniker", "denom"},
 		),
+		commissionShare: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validator_commission_share",
+				Help:        "Share of the outstanding rewards of the Cosmos-based blockchain validator which is its commission",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker", "denom"},
+		),
 
 		unbondingsGauge: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -178,6 +186,7 @@ func NewValidatorExtendedMetrics(reg prometheus.Registerer, config *ServiceConfi
 
 	reg.MustRegister(m.commissionGauge)
 	reg.MustRegister(m.rewardsGauge)
+	reg.MustRegister(m.commissionShare)
 	reg.MustRegister(m.unbondingsGauge)
 	reg.MustRegister(m.redelegationsGauge)
 
@@ -232,15 +241,10 @@ func GetValidatorBasicMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, met
 			Err(err).
 			Msg("Could not parse delegator shares")
 	} else {
-		metrics.delegatorSharesGauge.With(prometheus.Labels{
-			"address": validator.Validator.OperatorAddress,
-			"moniker": validator.Validator.Description.Moniker,
-			"denom":   config.Denom,
-		}).Set(value / config.DenomCoefficient)
+		metrics.delegatorSharesGauge.With(config.sharesLabels(validator.Validator.OperatorAddress, validator.Validator.Description.Moniker)).Set(value / config.DenomCoefficient)
 	}
 
-	// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
-	if rate, err := strconv.ParseFloat(validator.Validator.Commission.CommissionRates.Rate.String(), 64); err != nil {
+	if rate, err := RoundDecToFloat64(validator.Validator.Commission.CommissionRates.Rate, config.CommissionPrecision); err != nil {
 		sublogger.Error().
 			Str("address", validatorAddress.String()).
 			Err(err).
@@ -290,7 +294,7 @@ func GetValidatorBasicMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, met
 				Msg("Could not get unpack validator inferfaces")
 		}
 
-		pubKey, err := validator.Validator.GetConsAddr()
+		pubKey, err := ConsAddress(validator.Validator)
 		if err != nil {
 			sublogger.Error().
 				Str("address", validatorAddress.String()).
@@ -341,15 +345,22 @@ func getValidatorExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger,
 		queryStart := time.Now()
 
 		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
-		stakingRes, err := stakingClient.ValidatorDelegations(
-			context.Background(),
-			&stakingtypes.QueryValidatorDelegationsRequest{
-				ValidatorAddr: validatorAddress.String(),
-				Pagination: &querytypes.PageRequest{
-					Limit: config.Limit,
+		delegations, err := Paginate(func(key []byte) ([]stakingtypes.DelegationResponse, []byte, error) {
+			stakingRes, err := stakingClient.ValidatorDelegations(
+				context.Background(),
+				&stakingtypes.QueryValidatorDelegationsRequest{
+					ValidatorAddr: validatorAddress.String(),
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
 				},
-			},
-		)
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return stakingRes.DelegationResponses, stakingRes.Pagination.GetNextKey(), nil
+		})
 		if err != nil {
 			sublogger.Error().
 				Str("address", validatorAddress.String()).
@@ -363,7 +374,7 @@ func getValidatorExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger,
 			Float64("request-time", time.Since(queryStart).Seconds()).
 			Msg("Finished querying validator delegations")
 
-		for _, delegation := range stakingRes.DelegationResponses {
+		for _, delegation := range delegations {
 			value, err := strconv.ParseFloat(delegation.Balance.Amount.String(), 64)
 			if err != nil {
 				log.Error().
@@ -426,6 +437,67 @@ func getValidatorExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger,
 		}
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		sublogger.Debug().
+			Str("address", validatorAddress.String()).
+			Msg("Started querying validator commission share")
+		queryStart := time.Now()
+
+		distributionClient := distributiontypes.NewQueryClient(s.GrpcConn)
+		commissionRes, err := distributionClient.ValidatorCommission(
+			context.Background(),
+			&distributiontypes.QueryValidatorCommissionRequest{ValidatorAddress: validatorAddress.String()},
+		)
+		if err != nil {
+			sublogger.Error().
+				Str("address", validatorAddress.String()).
+				Err(err).
+				Msg("Could not get validator commission")
+			return
+		}
+
+		outstandingRes, err := distributionClient.ValidatorOutstandingRewards(
+			context.Background(),
+			&distributiontypes.QueryValidatorOutstandingRewardsRequest{ValidatorAddress: validatorAddress.String()},
+		)
+		if err != nil {
+			sublogger.Error().
+				Str("address", validatorAddress.String()).
+				Err(err).
+				Msg("Could not get validator rewards")
+			return
+		}
+
+		sublogger.Debug().
+			Str("address", validatorAddress.String()).
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying validator commission share")
+
+		// the outstanding rewards include the commission, which isn't paid out to delegators
+		for _, outstanding := range outstandingRes.Rewards.Rewards {
+			if !outstanding.Amount.IsPositive() {
+				continue
+			}
+
+			share := commissionRes.Commission.Commission.AmountOf(outstanding.Denom).Quo(outstanding.Amount)
+			if value, err := DecToFloat64(share); err != nil {
+				sublogger.Error().
+					Str("address", validatorAddress.String()).
+					Err(err).
+					Msg("Could not parse validator commission share")
+			} else {
+				metrics.commissionShare.With(prometheus.Labels{
+					"address": validatorAddress.String(),
+					"moniker": moniker,
+					"denom":   outstanding.Denom,
+				}).Set(value)
+			}
+		}
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -480,10 +552,22 @@ func getValidatorExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger,
 		queryStart := time.Now()
 
 		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
-		stakingRes, err := stakingClient.ValidatorUnbondingDelegations(
-			context.Background(),
-			&stakingtypes.QueryValidatorUnbondingDelegationsRequest{ValidatorAddr: validatorAddress.String()},
-		)
+		unbondings, err := Paginate(func(key []byte) ([]stakingtypes.UnbondingDelegation, []byte, error) {
+			stakingRes, err := stakingClient.ValidatorUnbondingDelegations(
+				context.Background(),
+				&stakingtypes.QueryValidatorUnbondingDelegationsRequest{
+					ValidatorAddr: validatorAddress.String(),
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return stakingRes.UnbondingResponses, stakingRes.Pagination.GetNextKey(), nil
+		})
 		if err != nil {
 			sublogger.Error().
 				Str("address", validatorAddress.String()).
@@ -497,7 +581,7 @@ func getValidatorExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger,
 			Float64("request-time", time.Since(queryStart).Seconds()).
 			Msg("Finished querying validator unbonding delegations")
 
-		for _, unbonding := range stakingRes.UnbondingResponses {
+		for _, unbonding := range unbondings {
 			var sum float64 = 0
 			for _, entry := range unbonding.Entries {
 				value, err := strconv.ParseFloat(entry.Balance.String(), 64)
@@ -530,10 +614,22 @@ func getValidatorExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger,
 		queryStart := time.Now()
 
 		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
-		stakingRes, err := stakingClient.Redelegations(
-			context.Background(),
-			&stakingtypes.QueryRedelegationsRequest{SrcValidatorAddr: validatorAddress.String()},
-		)
+		redelegations, err := Paginate(func(key []byte) ([]stakingtypes.RedelegationResponse, []byte, error) {
+			stakingRes, err := stakingClient.Redelegations(
+				context.Background(),
+				&stakingtypes.QueryRedelegationsRequest{
+					SrcValidatorAddr: validatorAddress.String(),
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return stakingRes.RedelegationResponses, stakingRes.Pagination.GetNextKey(), nil
+		})
 		if err != nil {
 			sublogger.Error().
 				Str("address", validatorAddress.String()).
@@ -547,7 +643,7 @@ func getValidatorExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger,
 			Float64("request-time", time.Since(queryStart).Seconds()).
 			Msg("Finished querying validator redelegations")
 
-		for _, redelegation := range stakingRes.RedelegationResponses {
+		for _, redelegation := range redelegations {
 			var sum float64 = 0
 			for _, entry := range redelegation.Entries {
 				value, err := strconv.ParseFloat(entry.Balance.String(), 64)
@@ -581,14 +677,21 @@ func getValidatorExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger,
 		queryStart := time.Now()
 
 		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
-		stakingRes, err := stakingClient.Validators(
-			context.Background(),
-			&stakingtypes.QueryValidatorsRequest{
-				Pagination: &querytypes.PageRequest{
-					Limit: config.Limit,
+		validators, err := Paginate(func(key []byte) ([]stakingtypes.Validator, []byte, error) {
+			stakingRes, err := stakingClient.Validators(
+				context.Background(),
+				&stakingtypes.QueryValidatorsRequest{
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
 				},
-			},
-		)
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return stakingRes.Validators, stakingRes.Pagination.GetNextKey(), nil
+		})
 		if err != nil {
 			sublogger.Error().
 				Str("address", validatorAddress.String()).
@@ -602,8 +705,6 @@ func getValidatorExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger,
 			Float64("request-time", time.Since(queryStart).Seconds()).
 			Msg("Finished querying validator other validators")
 
-		validators := stakingRes.Validators
-
 		// sorting by delegator shares to display rankings (unbonded go last)
 		sort.Slice(validators, func(i, j int) bool {
 			firstShares, firstErr := strconv.ParseFloat(validators[i].DelegatorShares.String(), 64)
@@ -684,38 +785,99 @@ func getValidatorExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger,
 
 }
 func (s *Service) ValidatorHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/validator")
 	requestStart := time.Now()
 	sublogger := s.Log.With().
-		Str("request-id", uuid.New().String()).
+		Str("request-id", RequestID(r)).
 		Logger()
 
 	address := r.URL.Query().Get("address")
-	myAddress, err := sdk.ValAddressFromBech32(address)
-	if err != nil {
-		sublogger.Error().
-			Str("address", address).
-			Err(err).
-			Msg("Could not get address")
-		return
+	moniker := r.URL.Query().Get("moniker")
+
+	var addresses []sdk.ValAddress
+	if address == "" && moniker != "" {
+		var err error
+		addresses, err = s.findValidatorsByMoniker(&sublogger, moniker)
+		if err != nil {
+			sublogger.Error().
+				Str("moniker", moniker).
+				Err(err).
+				Msg("Could not get validators")
+			return
+		}
+	} else {
+		myAddress, err := sdk.ValAddressFromBech32(address)
+		if err != nil {
+			sublogger.Error().
+				Str("address", address).
+				Err(err).
+				Msg("Could not get address")
+			return
+		}
+		addresses = append(addresses, myAddress)
 	}
 
-	registry := prometheus.NewRegistry()
+	registry := s.Config.NewRegistry()
 	validatorMetrics := NewValidatorMetrics(registry, s.Config)
 	validatorExtendedMetrics := NewValidatorExtendedMetrics(registry, s.Config)
 	var wg sync.WaitGroup
 
-	validator := GetValidatorBasicMetrics(&wg, &sublogger, validatorMetrics, s, s.Config, myAddress)
-	if validator != nil {
-		getValidatorExtendedMetrics(&wg, &sublogger, validatorExtendedMetrics, s, s.Config, myAddress, validator.Validator.Description.Moniker, validator)
+	for _, myAddress := range addresses {
+		validator := GetValidatorBasicMetrics(&wg, &sublogger, validatorMetrics, s, s.Config, myAddress)
+		if validator != nil {
+			getValidatorExtendedMetrics(&wg, &sublogger, validatorExtendedMetrics, s, s.Config, myAddress, validator.Validator.Description.Moniker, validator)
+		}
 	}
 
 	wg.Wait()
 
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	h.ServeHTTP(w, r)
+	s.ServeMetrics(w, r, registry)
 	sublogger.Info().
 		Str("method", "GET").
-		Str("endpoint", "/metrics/validator?address="+address).
+		Str("endpoint", "/metrics/validator?"+r.URL.RawQuery).
 		Float64("request-time", time.Since(requestStart).Seconds()).
 		Msg("Request processed")
 }
+
+// maxMonikerMatches caps the validators served for a moniker query param, as a short one can match half the set
+const maxMonikerMatches = 5
+
+// findValidatorsByMoniker returns the validators whose moniker contains the substring, case-insensitive
+func (s *Service) findValidatorsByMoniker(sublogger *zerolog.Logger, moniker string) ([]sdk.ValAddress, error) {
+	validators, err := s.GetValidators()
+	if err != nil {
+		return nil, err
+	}
+
+	var addresses []sdk.ValAddress
+	matches := 0
+	for _, validator := range validators {
+		if !strings.Contains(strings.ToLower(validator.Description.Moniker), strings.ToLower(moniker)) {
+			continue
+		}
+
+		matches++
+		if len(addresses) == maxMonikerMatches {
+			continue
+		}
+
+		address, err := sdk.ValAddressFromBech32(validator.OperatorAddress)
+		if err != nil {
+			sublogger.Error().
+				Str("address", validator.OperatorAddress).
+				Err(err).
+				Msg("Could not get address")
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+
+	if matches > 1 {
+		sublogger.Warn().
+			Str("moniker", moniker).
+			Int("matches", matches).
+			Int("served", len(addresses)).
+			Msg("Moniker matches several validators")
+	}
+	return addresses, nil
+}
@@ -0,0 +1,155 @@
+package exporter
+
+import (
+	"github.com/rs/zerolog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type ValidatorSetMetrics struct {
+	setMismatchGauge   *prometheus.GaugeVec
+	mismatchesGauge    prometheus.Gauge
+	cometbftPowerGauge *prometheus.GaugeVec
+}
+
+func NewValidatorSetMetrics(reg prometheus.Registerer, config *ServiceConfig) *ValidatorSetMetrics {
+	m := &ValidatorSetMetrics{
+		setMismatchGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_set_mismatch",
+				Help:        "1 if the bonded Cosmos-based blockchain validator is missing from the CometBFT validator set, 0 if no",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		mismatchesGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_set_mismatches",
+				Help:        "Number of validators in only one of the bonded staking validators and the CometBFT validator set",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		cometbftPowerGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_cometbft_power",
+				Help:        "Voting power of the Cosmos-based blockchain validator in the CometBFT validator set",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+	}
+	reg.MustRegister(m.setMismatchGauge)
+	reg.MustRegister(m.mismatchesGauge)
+	reg.MustRegister(m.cometbftPowerGauge)
+	return m
+}
+func GetValidatorSetMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *ValidatorSetMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying validator sets")
+		queryStart := time.Now()
+
+		cs, err := s.chainStatus()
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get chain status")
+			return
+		}
+
+		validatorSet, err := cs.ValidatorSet(cs.SyncInfo().LatestBlockHeight)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get validator set")
+			return
+		}
+
+		validators, err := s.GetValidators()
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get validators")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying validator sets")
+
+		validatorsByConsAddress := ValidatorsByConsAddress(sublogger, validators)
+
+		// the staking changes only reach CometBFT a couple of blocks later, so a mismatch
+		// is expected right after the active set changed, but not for long
+		mismatches := 0
+		inValidatorSet := make(map[string]bool, len(validatorSet))
+		for _, consensusValidator := range validatorSet {
+			inValidatorSet[consensusValidator.Address] = true
+
+			validator, ok := validatorsByConsAddress[consensusValidator.Address]
+			if !ok || !validator.IsBonded() {
+				sublogger.Warn().
+					Str("consensus-address", consensusValidator.Address).
+					Msg("CometBFT validator doesn't match any bonded staking validator")
+				mismatches++
+			}
+			if !ok {
+				continue
+			}
+
+			// the authoritative consensus weight, which can differ from cosmos_validators_voting_power
+			// if the exporter's --power-reduction doesn't match the chain
+			metrics.cometbftPowerGauge.With(prometheus.Labels{
+				"address": validator.OperatorAddress,
+				"moniker": validator.Description.Moniker,
+			}).Set(float64(consensusValidator.VotingPower))
+		}
+
+		for consAddress, validator := range validatorsByConsAddress {
+			if !validator.IsBonded() {
+				continue
+			}
+
+			// golang doesn't have a ternary operator, so we have to stick with this ugly solution
+			var setMismatch float64
+
+			if inValidatorSet[consAddress] {
+				setMismatch = 0
+			} else {
+				sublogger.Warn().
+					Str("address", validator.OperatorAddress).
+					Msg("Bonded validator is missing from the CometBFT validator set")
+				setMismatch = 1
+				mismatches++
+			}
+			metrics.setMismatchGauge.With(prometheus.Labels{
+				"address": validator.OperatorAddress,
+				"moniker": validator.Description.Moniker,
+			}).Set(setMismatch)
+		}
+		metrics.mismatchesGauge.Set(float64(mismatches))
+	}()
+
+}
+func (s *Service) ValidatorSetHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/validator-set")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	validatorSetMetrics := NewValidatorSetMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetValidatorSetMetrics(&wg, &sublogger, validatorSetMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/validator-set").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
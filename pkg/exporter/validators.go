@@ -3,129 +3,449 @@ package exporter
 import (
 	"context"
 	"encoding/hex"
+	"fmt"
 	crytpocode "github.com/cosmos/cosmos-sdk/crypto/codec"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"math/big"
 	"net/http"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
 	querytypes "github.com/cosmos/cosmos-sdk/types/query"
 	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
-	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+type ValidatorsMetrics struct {
+	commissionGauge                  *prometheus.GaugeVec
+	commissionVsAvgGauge             *prometheus.GaugeVec
+	commissionBpsGauge               *prometheus.GaugeVec
+	commissionUpdateTimeGauge        *prometheus.GaugeVec
+	belowMinCommissionGauge          *prometheus.GaugeVec
+	statusGauge                      *prometheus.GaugeVec
+	jailedGauge                      *prometheus.GaugeVec
+	unjailableNowGauge               *prometheus.GaugeVec
+	jailedCountGauge                 prometheus.Gauge
+	tokensGauge                      *prometheus.GaugeVec
+	tokensDistribution               prometheus.Histogram
+	delegationInflowCounter          *prometheus.CounterVec
+	delegationOutflowCounter         *prometheus.CounterVec
+	votingPowerGauge                 *prometheus.GaugeVec
+	delegatorSharesGauge             *prometheus.GaugeVec
+	minSelfDelegationGauge           *prometheus.GaugeVec
+	missedBlocksGauge                *prometheus.GaugeVec
+	signingInfoAvailableGauge        *prometheus.GaugeVec
+	signingWindowResetsCounter       *prometheus.CounterVec
+	unjailEventsCounter              *prometheus.CounterVec
+	missedRatioGauge                 *prometheus.GaugeVec
+	missedRatioThresholdGauge        prometheus.Gauge
+	rankGauge                        *prometheus.GaugeVec
+	activeRankGauge                  *prometheus.GaugeVec
+	rankDeltaGauge                   *prometheus.GaugeVec
+	bondedSecondsGauge               *prometheus.GaugeVec
+	slotsUsedGauge                   prometheus.Gauge
+	slotsTotalGauge                  prometheus.Gauge
+	monikerCollisionGauge            *prometheus.GaugeVec
+	isActiveGauge                    *prometheus.GaugeVec
+	selfDelegationGauge              *prometheus.GaugeVec
+	delegationLeverageGauge          *prometheus.GaugeVec
+	unbondingEntriesGauge            *prometheus.GaugeVec
+	withUnbondingGauge               prometheus.Gauge
+	signingInfoFallbackTimeoutsGauge prometheus.Gauge
+	signingCoverageGauge             prometheus.Gauge
+	fetchedGauge                     prometheus.Gauge
+	missingSigningInfosGauge         prometheus.Gauge
+}
+
+func NewValidatorsMetrics(reg prometheus.Registerer, config *ServiceConfig) *ValidatorsMetrics {
+	return newValidatorsMetrics(reg, config, false)
+}
+
+// newValidatorsMetrics only registers the metrics served by the minimal scrapes when minimal is set
+func newValidatorsMetrics(reg prometheus.Registerer, config *ServiceConfig, minimal bool) *ValidatorsMetrics {
+	m := &ValidatorsMetrics{
+		commissionGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_commission",
+				Help:        "Commission of the Cosmos-based blockchain validator",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		commissionVsAvgGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_commission_vs_avg",
+				Help:        "Commission of the Cosmos-based blockchain validator minus the average commission of the bonded validators",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		commissionBpsGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_commission_bps",
+				Help:        "Commission of the Cosmos-based blockchain validator in basis points",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		commissionUpdateTimeGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_commission_update_time",
+				Help:        "Time the commission of the Cosmos-based blockchain validator last changed at, as unix timestamp",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		belowMinCommissionGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_below_min_commission",
+				Help:        "1 if the commission of the Cosmos-based blockchain validator is below the chain min commission rate, 0 if no",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		statusGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_status",
+				Help:        "Status of the Cosmos-based blockchain validator",
+				ConstLabels: config.ConstLabels,
+			},
+			config.statusLabelNames(),
+		),
+		jailedGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_jailed",
+				Help:        "Jailed status of the Cosmos-based blockchain validator",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		unjailableNowGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_unjailable_now",
+				Help:        "Whether an unjail transaction of the jailed Cosmos-based blockchain validator would succeed now",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		jailedCountGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_jailed_count",
+				Help:        "Number of jailed validators of the Cosmos-based blockchain",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		tokensGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_tokens",
+				Help:        "Tokens of the Cosmos-based blockchain validator",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker", "denom"},
+		),
+		tokensDistribution: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:        "cosmos_validators_tokens_distribution",
+				Help:        "Distribution of the tokens of the Cosmos-based blockchain validators",
+				ConstLabels: config.ConstLabels,
+				Buckets:     prometheus.ExponentialBuckets(100, 10, 8),
+			},
+		),
+		delegationInflowCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "cosmos_validators_delegation_inflow",
+				Help:        "Tokens gained by the Cosmos-based blockchain validator between scrapes, since the exporter started",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker", "denom"},
+		),
+		delegationOutflowCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "cosmos_validators_delegation_outflow",
+				Help:        "Tokens lost by the Cosmos-based blockchain validator between scrapes, since the exporter started",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker", "denom"},
+		),
+		votingPowerGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_voting_power",
+				Help:        "Consensus power of the Cosmos-based blockchain validator, its tokens divided by the power reduction",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		delegatorSharesGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_delegator_shares",
+				Help:        "Delegator shares of the Cosmos-based blockchain validator",
+				ConstLabels: config.ConstLabels,
+			},
+			config.sharesLabelNames(),
+		),
+		minSelfDelegationGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_min_self_delegation",
+				Help:        "Self declared minimum self delegation of the Cosmos-based blockchain validator, in the denom of the label",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker", "denom"},
+		),
+		missedBlocksGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_missed_blocks",
+				Help:        "Missed blocks of the Cosmos-based blockchain validator",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		signingInfoAvailableGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_signing_info_available",
+				Help:        "1 if the signing info of the bonded Cosmos-based blockchain validator could be queried, 0 if no and its missed blocks are unknown",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		signingWindowResetsCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "cosmos_validators_signing_window_resets_total",
+				Help:        "Times the missed blocks counter of the Cosmos-based blockchain validator decreased between scrapes, since the exporter started",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		unjailEventsCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "cosmos_validators_unjail_events_total",
+				Help:        "Times the Cosmos-based blockchain validator went from jailed to not jailed between scrapes, since the exporter started",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		missedRatioGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_missed_ratio",
+				Help:        "Missed blocks of the Cosmos-based blockchain validator divided by the signed blocks window",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		missedRatioThresholdGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_missed_ratio_threshold",
+				Help:        "Missed blocks ratio above which validators get jailed, 1 - min_signed_per_window",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		rankGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_rank",
+				Help:        "Rank of the Cosmos-based blockchain validator",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		activeRankGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_active_rank",
+				Help:        "Rank of the Cosmos-based blockchain validator among the bonded validators only",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		rankDeltaGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_rank_delta",
+				Help:        "Change of the rank of the Cosmos-based blockchain validator since the previous scrape, positive if it climbed",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		bondedSecondsGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_bonded_seconds",
+				Help:        "Seconds the Cosmos-based blockchain validator has continuously been bonded for, since the exporter started",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		slotsUsedGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_staking_validator_slots_used",
+				Help:        "Number of bonded validators, taking the slots of the active set",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		slotsTotalGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_staking_validator_slots_total",
+				Help:        "Number of slots of the active set, the max validators staking param",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		monikerCollisionGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_moniker_collision",
+				Help:        "1 if the moniker of the Cosmos-based blockchain validator is shared by another validator, 0 if no",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		isActiveGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_active",
+				Help:        "1 if the Cosmos-based blockchain validator is in active set, 0 if no",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "pubkey_hash", "moniker"},
+		),
+		selfDelegationGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_self_delegation",
+				Help:        "Tokens self delegated by the operator of the Cosmos-based blockchain validator",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker", "denom"},
+		),
+		delegationLeverageGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_delegation_leverage",
+				Help:        "Tokens delegated to the Cosmos-based blockchain validator by others divided by its self delegation",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		unbondingEntriesGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_unbonding_entries",
+				Help:        "Pending unbonding delegation entries of the Cosmos-based blockchain validator",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "moniker"},
+		),
+		withUnbondingGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_validators_with_unbonding",
+				Help:        "Number of Cosmos-based blockchain validators with pending unbonding delegations",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		signingInfoFallbackTimeoutsGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_exporter_signing_info_fallback_timeouts",
+				Help:        "Signing infos queried one by one which were dropped after --fallback-query-timeout in this scrape",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		signingCoverageGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_exporter_signing_coverage",
+				Help:        "Ratio of the bonded validators whose signing info was found, from the bulk query or one by one, below 1 if the slashing data is incomplete",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		fetchedGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_exporter_validators_fetched",
+				Help:        "Number of validators fetched from all the pages of the staking validators query",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		missingSigningInfosGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_exporter_missing_signing_infos",
+				Help:        "Bonded validators missing from the bulk signing infos query, queried one by one instead",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+	}
+	// the minimal scrapes only serve these
+	reg.MustRegister(m.statusGauge)
+	reg.MustRegister(m.jailedGauge)
+	reg.MustRegister(m.jailedCountGauge)
+	reg.MustRegister(m.unjailableNowGauge)
+	reg.MustRegister(m.unjailEventsCounter)
+	reg.MustRegister(m.missedBlocksGauge)
+	reg.MustRegister(m.signingInfoAvailableGauge)
+	reg.MustRegister(m.signingWindowResetsCounter)
+	reg.MustRegister(m.missedRatioGauge)
+	reg.MustRegister(m.missedRatioThresholdGauge)
+	reg.MustRegister(m.isActiveGauge)
+	reg.MustRegister(m.missingSigningInfosGauge)
+	reg.MustRegister(m.fetchedGauge)
+	reg.MustRegister(m.signingCoverageGauge)
+	reg.MustRegister(m.signingInfoFallbackTimeoutsGauge)
+	if !minimal {
+		reg.MustRegister(m.commissionGauge)
+		reg.MustRegister(m.commissionVsAvgGauge)
+		if config.CommissionBps {
+			reg.MustRegister(m.commissionBpsGauge)
+		}
+		reg.MustRegister(m.commissionUpdateTimeGauge)
+		reg.MustRegister(m.belowMinCommissionGauge)
+		if config.TokensHistogram {
+			reg.MustRegister(m.tokensDistribution)
+		} else {
+			reg.MustRegister(m.tokensGauge)
+		}
+		reg.MustRegister(m.delegationInflowCounter)
+		reg.MustRegister(m.delegationOutflowCounter)
+		reg.MustRegister(m.votingPowerGauge)
+		reg.MustRegister(m.delegatorSharesGauge)
+		reg.MustRegister(m.minSelfDelegationGauge)
+		reg.MustRegister(m.rankGauge)
+		reg.MustRegister(m.rankDeltaGauge)
+		reg.MustRegister(m.activeRankGauge)
+		reg.MustRegister(m.bondedSecondsGauge)
+		reg.MustRegister(m.monikerCollisionGauge)
+		reg.MustRegister(m.slotsUsedGauge)
+		reg.MustRegister(m.slotsTotalGauge)
+		if config.SelfDelegation {
+			reg.MustRegister(m.selfDelegationGauge)
+			reg.MustRegister(m.delegationLeverageGauge)
+		}
+		if config.UnbondingEntries {
+			reg.MustRegister(m.unbondingEntriesGauge)
+			reg.MustRegister(m.withUnbondingGauge)
+		}
+	}
+	return m
+}
 func (s *Service) ValidatorsHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/validators")
 	interfaceRegistry := codectypes.NewInterfaceRegistry()
 	crytpocode.RegisterInterfaces(interfaceRegistry)
 
 	requestStart := time.Now()
 	config := s.Config
 	sublogger := s.Log.With().
-		Str("request-id", uuid.New().String()).
+		Str("request-id", RequestID(r)).
 		Logger()
+	denomCoefficient := s.denomCoefficient(&sublogger)
+	minimal := MinimalScrape(r)
 
-	validatorsCommissionGauge := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name:        "cosmos_validators_commission",
-			Help:        "Commission of the Cosmos-based blockchain validator",
-			ConstLabels: config.ConstLabels,
-		},
-		[]string{"address", "moniker"},
-	)
-
-	validatorsStatusGauge := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name:        "cosmos_validators_status",
-			Help:        "Status of the Cosmos-based blockchain validator",
-			ConstLabels: config.ConstLabels,
-		},
-		[]string{"address", "moniker"},
-	)
-
-	validatorsJailedGauge := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name:        "cosmos_validators_jailed",
-			Help:        "Jailed status of the Cosmos-based blockchain validator",
-			ConstLabels: config.ConstLabels,
-		},
-		[]string{"address", "moniker"},
-	)
-
-	validatorsTokensGauge := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name:        "cosmos_validators_tokens",
-			Help:        "Tokens of the Cosmos-based blockchain validator",
-			ConstLabels: config.ConstLabels,
-		},
-		[]string{"address", "moniker", "denom"},
-	)
-
-	validatorsDelegatorSharesGauge := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name:        "cosmos_validators_delegator_shares",
-			Help:        "Delegator shares of the Cosmos-based blockchain validator",
-			ConstLabels: config.ConstLabels,
-		},
-		[]string{"address", "moniker", "denom"},
-	)
-
-	validatorsMinSelfDelegationGauge := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name:        "cosmos_validators_min_self_delegation",
-			Help:        "Self declared minimum self delegation shares of the Cosmos-based blockchain validator",
-			ConstLabels: config.ConstLabels,
-		},
-		[]string{"address", "moniker", "denom"},
-	)
-
-	validatorsMissedBlocksGauge := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name:        "cosmos_validators_missed_blocks",
-			Help:        "Missed blocks of the Cosmos-based blockchain validator",
-			ConstLabels: config.ConstLabels,
-		},
-		[]string{"address", "moniker"},
-	)
-
-	validatorsRankGauge := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name:        "cosmos_validators_rank",
-			Help:        "Rank of the Cosmos-based blockchain validator",
-			ConstLabels: config.ConstLabels,
-		},
-		[]string{"address", "moniker"},
-	)
-
-	validatorsIsActiveGauge := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name:        "cosmos_validators_active",
-			Help:        "1 if the Cosmos-based blockchain validator is in active set, 0 if no",
-			ConstLabels: config.ConstLabels,
-		},
-		[]string{"address", "pubkey_hash", "moniker"},
-	)
-
-	registry := prometheus.NewRegistry()
-	registry.MustRegister(validatorsCommissionGauge)
-	registry.MustRegister(validatorsStatusGauge)
-	registry.MustRegister(validatorsJailedGauge)
-	registry.MustRegister(validatorsTokensGauge)
-	registry.MustRegister(validatorsDelegatorSharesGauge)
-	registry.MustRegister(validatorsMinSelfDelegationGauge)
-	registry.MustRegister(validatorsMissedBlocksGauge)
-	registry.MustRegister(validatorsRankGauge)
-	registry.MustRegister(validatorsIsActiveGauge)
+	registry := config.NewRegistry()
+	metrics := newValidatorsMetrics(registry, config, minimal)
 
 	var validators []stakingtypes.Validator
 	var signingInfos []slashingtypes.ValidatorSigningInfo
 	var validatorSetLength uint32
+	var minCommissionRate sdk.Dec
+	var signedBlocksWindow int64
+	// logged with the request, to see which query makes a scrape slow
+	var validatorsQueryTime, signingInfosQueryTime, stakingParamsQueryTime, slashingParamsQueryTime time.Duration
 
 	var wg sync.WaitGroup
 
@@ -135,47 +455,19 @@ func (s *Service) ValidatorsHandler(w http.ResponseWriter, r *http.Request) {
 		sublogger.Debug().Msg("Started querying validators")
 		queryStart := time.Now()
 
-		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
-
-		offset := uint64(0)
-		for {
-			validatorsResponse, err := stakingClient.Validators(
-				context.Background(),
-				&stakingtypes.QueryValidatorsRequest{
-					Pagination: &querytypes.PageRequest{
-						Limit:  config.Limit,
-						Offset: offset,
-					},
-				},
-			)
-
-			if err != nil {
-				sublogger.Error().Err(err).Msg("Could not get validators")
-				return
-			}
-
-			validatorsOnPage := validatorsResponse.GetValidators()
-			if validatorsResponse == nil || len(validatorsOnPage) == 0 {
-				break
-			}
-			validators = append(validators, validatorsOnPage...)
-			offset = uint64(len(validators))
+		var err error
+		validators, err = s.GetValidators()
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get validators")
+			return
 		}
 
+		validatorsQueryTime = time.Since(queryStart)
 		sublogger.Debug().
-			Float64("request-time", time.Since(queryStart).Seconds()).
+			Float64("request-time", validatorsQueryTime.Seconds()).
 			Msg("Finished querying validators")
 
-		// sorting by delegator shares to display rankings (unbonded go last)
-		sort.Slice(validators, func(i, j int) bool {
-			if !validators[i].IsBonded() && validators[j].IsBonded() {
-				return false
-			} else if validators[i].IsBonded() && !validators[j].IsBonded() {
-				return true
-			}
-
-			return validators[i].DelegatorShares.BigInt().Cmp(validators[j].DelegatorShares.BigInt()) > 0
-		})
+		SortValidators(validators)
 	}()
 
 	wg.Add(1)
@@ -185,14 +477,21 @@ func (s *Service) ValidatorsHandler(w http.ResponseWriter, r *http.Request) {
 		queryStart := time.Now()
 
 		slashingClient := slashingtypes.NewQueryClient(s.GrpcConn)
-		signingInfosResponse, err := slashingClient.SigningInfos(
-			context.Background(),
-			&slashingtypes.QuerySigningInfosRequest{
-				Pagination: &querytypes.PageRequest{
-					Limit: config.Limit,
+		pages, err := Paginate(func(key []byte) ([]slashingtypes.ValidatorSigningInfo, []byte, error) {
+			signingInfosResponse, err := slashingClient.SigningInfos(
+				context.Background(),
+				&slashingtypes.QuerySigningInfosRequest{
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
 				},
-			},
-		)
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return signingInfosResponse.Info, signingInfosResponse.Pagination.GetNextKey(), nil
+		})
 		if err != nil {
 			sublogger.Error().
 				Err(err).
@@ -200,10 +499,11 @@ func (s *Service) ValidatorsHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		signingInfosQueryTime = time.Since(queryStart)
 		sublogger.Debug().
-			Float64("request-time", time.Since(queryStart).Seconds()).
+			Float64("request-time", signingInfosQueryTime.Seconds()).
 			Msg("Finished querying validator signing infos")
-		signingInfos = signingInfosResponse.Info
+		signingInfos = pages
 	}()
 
 	wg.Add(1)
@@ -212,11 +512,7 @@ func (s *Service) ValidatorsHandler(w http.ResponseWriter, r *http.Request) {
 		sublogger.Debug().Msg("Started querying staking params")
 		queryStart := time.Now()
 
-		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
-		paramsResponse, err := stakingClient.Params(
-			context.Background(),
-			&stakingtypes.QueryParamsRequest{},
-		)
+		params, err := s.GetStakingParams()
 		if err != nil {
 			sublogger.Error().
 				Err(err).
@@ -224,10 +520,47 @@ func (s *Service) ValidatorsHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		stakingParamsQueryTime = time.Since(queryStart)
 		sublogger.Debug().
-			Float64("request-time", time.Since(queryStart).Seconds()).
+			Float64("request-time", stakingParamsQueryTime.Seconds()).
 			Msg("Finished querying staking params")
-		validatorSetLength = paramsResponse.Params.MaxValidators
+		validatorSetLength = params.MaxValidators
+		// nil on the chains whose staking module doesn't have the param
+		minCommissionRate = params.MinCommissionRate
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying slashing params")
+		queryStart := time.Now()
+
+		slashingClient := slashingtypes.NewQueryClient(s.GrpcConn)
+		paramsResponse, err := slashingClient.Params(
+			context.Background(),
+			&slashingtypes.QueryParamsRequest{},
+		)
+		if err != nil {
+			sublogger.Error().
+				Err(err).
+				Msg("Could not get slashing params")
+			return
+		}
+
+		slashingParamsQueryTime = time.Since(queryStart)
+		sublogger.Debug().
+			Float64("request-time", slashingParamsQueryTime.Seconds()).
+			Msg("Finished querying slashing params")
+		signedBlocksWindow = paramsResponse.Params.SignedBlocksWindow
+
+		// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+		if value, err := strconv.ParseFloat(paramsResponse.Params.MinSignedPerWindow.String(), 64); err != nil {
+			sublogger.Error().
+				Err(err).
+				Msg("Could not parse min signed per window")
+		} else {
+			metrics.missedRatioThresholdGauge.Set(1 - value)
+		}
 	}()
 
 	wg.Wait()
@@ -237,83 +570,273 @@ func (s *Service) ValidatorsHandler(w http.ResponseWriter, r *http.Request) {
 		Int("validatorsLength", len(validators)).
 		Msg("Validators info")
 
+	signingInfosByAddress := IndexSigningInfos(signingInfos)
+	var previousRanks map[string]int
+	if !minimal {
+		// not swapped by the minimal scrapes, so the rank changes are the ones since the previous full scrape
+		previousRanks = s.swapValidatorRanks(validators)
+	}
+	bondedSince := s.updateBondedSince(validators)
+
+	// impersonators usually only change the case or add spaces
+	monikers := make(map[string]int, len(validators))
+	for _, validator := range validators {
+		monikers[normalizeMoniker(validator.Description.Moniker)]++
+	}
+
+	var averageCommission sdk.Dec
+	var hasAverageCommission bool
+	if !minimal {
+		averageCommission, hasAverageCommission = AverageBondedCommission(validators)
+	}
+
+	activeSet := ActiveSet(validators, validatorSetLength, tombstonedValidators(validators, signingInfosByAddress, interfaceRegistry))
 	activeValidators := 0
+	bondedValidators := 0
+	missingSigningInfos := 0
+	bondedSigningInfos := 0
+	signingInfoFallbacks := 0
+	signingInfoFallbackTimeouts := 0
+	jailedValidators := 0
+	var notServed []string
+	var withUnbonding atomic.Int64
 	for index, validator := range validators {
-		// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
-		rate, err := strconv.ParseFloat(validator.Commission.CommissionRates.Rate.String(), 64)
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("address", validator.OperatorAddress).
-				Msg("Could not get commission")
-		} else {
-			validatorsCommissionGauge.With(prometheus.Labels{
+		served := config.ServeValidator(index, validator.OperatorAddress)
+		if validator.Status == stakingtypes.Bonded {
+			bondedValidators++
+		}
+		if !served {
+			// still going through it for the active set and the counts, its series are deleted below
+			notServed = append(notServed, validator.OperatorAddress)
+		}
+
+		if !minimal {
+			rate, err := RoundDecToFloat64(validator.Commission.CommissionRates.Rate, config.CommissionPrecision)
+			if err != nil {
+				log.Error().
+					Err(err).
+					Str("address", validator.OperatorAddress).
+					Msg("Could not get commission")
+			} else {
+				metrics.commissionGauge.With(prometheus.Labels{
+					"address": validator.OperatorAddress,
+					"moniker": validator.Description.Moniker,
+				}).Set(rate)
+			}
+
+			if hasAverageCommission && !validator.Commission.CommissionRates.Rate.IsNil() {
+				// negative for the validators cheaper than the average
+				if delta, err := RoundDecToFloat64(validator.Commission.CommissionRates.Rate.Sub(averageCommission), config.CommissionPrecision); err != nil {
+					log.Error().
+						Err(err).
+						Str("address", validator.OperatorAddress).
+						Msg("Could not get commission vs average")
+				} else {
+					metrics.commissionVsAvgGauge.With(prometheus.Labels{
+						"address": validator.OperatorAddress,
+						"moniker": validator.Description.Moniker,
+					}).Set(delta)
+				}
+			}
+
+			if config.CommissionBps {
+				// rounded with the dec so alert rules can compare against exact integers
+				metrics.commissionBpsGauge.With(prometheus.Labels{
+					"address": validator.OperatorAddress,
+					"moniker": validator.Description.Moniker,
+				}).Set(float64(validator.Commission.CommissionRates.Rate.MulInt64(10000).RoundInt64()))
+			}
+
+			// the update time of the validators which never changed it is their creation
+			metrics.commissionUpdateTimeGauge.With(prometheus.Labels{
 				"address": validator.OperatorAddress,
 				"moniker": validator.Description.Moniker,
-			}).Set(rate)
+			}).Set(float64(validator.Commission.UpdateTime.Unix()))
+
+			if !minCommissionRate.IsNil() {
+				// golang doesn't have a ternary operator, so we have to stick with this ugly solution
+				var belowMinCommission float64
+
+				if validator.Commission.CommissionRates.Rate.LT(minCommissionRate) {
+					belowMinCommission = 1
+				} else {
+					belowMinCommission = 0
+				}
+				metrics.belowMinCommissionGauge.With(prometheus.Labels{
+					"address": validator.OperatorAddress,
+					"moniker": validator.Description.Moniker,
+				}).Set(belowMinCommission)
+			}
 		}
 
-		validatorsStatusGauge.With(prometheus.Labels{
-			"address": validator.OperatorAddress,
-			"moniker": validator.Description.Moniker,
-		}).Set(float64(validator.Status))
+		metrics.statusGauge.With(config.statusLabels(validator)).Set(float64(validator.Status))
 
 		// golang doesn't have a ternary operator, so we have to stick with this ugly solution
 		var jailed float64
 
 		if validator.Jailed {
 			jailed = 1
+			jailedValidators++
 		} else {
 			jailed = 0
 		}
-		validatorsJailedGauge.With(prometheus.Labels{
+		metrics.jailedGauge.With(prometheus.Labels{
 			"address": validator.OperatorAddress,
 			"moniker": validator.Description.Moniker,
 		}).Set(jailed)
 
-		// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
-		if value, err := strconv.ParseFloat(validator.Tokens.String(), 64); err != nil {
-			sublogger.Error().
-				Str("address", validator.OperatorAddress).
-				Err(err).
-				Msg("Could not parse delegator tokens")
-		} else {
-			validatorsTokensGauge.With(prometheus.Labels{
+		if events, jailedFor, ok := s.updateUnjailEvents(validator.OperatorAddress, validator.Jailed); ok {
+			if jailedFor > 0 {
+				sublogger.Info().
+					Str("address", validator.OperatorAddress).
+					Str("moniker", validator.Description.Moniker).
+					Dur("jailed-for", jailedFor).
+					Msg("Validator was unjailed")
+			}
+			metrics.unjailEventsCounter.With(prometheus.Labels{
 				"address": validator.OperatorAddress,
 				"moniker": validator.Description.Moniker,
-				"denom":   config.Denom,
-			}).Set(value / config.DenomCoefficient) // a better way to do this is using math/big Div then checking IsInt64
+			}).Add(events)
 		}
 
-		// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
-		if value, err := strconv.ParseFloat(validator.DelegatorShares.String(), 64); err != nil {
-			sublogger.Error().
-				Str("address", validator.OperatorAddress).
-				Err(err).
-				Msg("Could not parse delegator shares")
-		} else {
-			validatorsDelegatorSharesGauge.With(prometheus.Labels{
-				"address": validator.OperatorAddress,
-				"moniker": validator.Description.Moniker,
-				"denom":   config.Denom,
-			}).Set(value / config.DenomCoefficient)
-		}
+		if !minimal {
+			// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+			if value, err := strconv.ParseFloat(validator.Tokens.String(), 64); err != nil {
+				sublogger.Error().
+					Str("address", validator.OperatorAddress).
+					Err(err).
+					Msg("Could not parse delegator tokens")
+			} else {
+				if config.TokensHistogram {
+					metrics.tokensDistribution.Observe(value / denomCoefficient)
+				} else {
+					metrics.tokensGauge.With(prometheus.Labels{
+						"address": validator.OperatorAddress,
+						"moniker": validator.Description.Moniker,
+						"denom":   config.Denom,
+					}).Set(value / denomCoefficient) // a better way to do this is using math/big Div then checking IsInt64
+				}
 
-		// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
-		if value, err := strconv.ParseFloat(validator.MinSelfDelegation.String(), 64); err != nil {
-			sublogger.Error().
-				Str("address", validator.OperatorAddress).
-				Err(err).
-				Msg("Could not parse validator min self delegation")
-		} else {
-			validatorsMinSelfDelegationGauge.With(prometheus.Labels{
-				"address": validator.OperatorAddress,
-				"moniker": validator.Description.Moniker,
-				"denom":   config.Denom,
-			}).Set(value / config.DenomCoefficient)
+				if flow, ok := s.updateStakeFlow(validator.OperatorAddress, value/denomCoefficient); ok {
+					metrics.delegationInflowCounter.With(prometheus.Labels{
+						"address": validator.OperatorAddress,
+						"moniker": validator.Description.Moniker,
+						"denom":   config.Denom,
+					}).Add(flow.inflow)
+					metrics.delegationOutflowCounter.With(prometheus.Labels{
+						"address": validator.OperatorAddress,
+						"moniker": validator.Description.Moniker,
+						"denom":   config.Denom,
+					}).Add(flow.outflow)
+				}
+			}
+
+			if config.InRankBand(index + 1) {
+				metrics.votingPowerGauge.With(prometheus.Labels{
+					"address": validator.OperatorAddress,
+					"moniker": validator.Description.Moniker,
+				}).Set(float64(validator.ConsensusPower(config.PowerReductionInt())))
+			}
+
+			// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+			if value, err := strconv.ParseFloat(validator.DelegatorShares.String(), 64); err != nil {
+				sublogger.Error().
+					Str("address", validator.OperatorAddress).
+					Err(err).
+					Msg("Could not parse delegator shares")
+			} else {
+				metrics.delegatorSharesGauge.With(config.sharesLabels(validator.OperatorAddress, validator.Description.Moniker)).Set(value / denomCoefficient)
+			}
+
+			if value, err := IntToFloat64(validator.MinSelfDelegation, denomCoefficient); err != nil {
+				sublogger.Error().
+					Str("address", validator.OperatorAddress).
+					Err(err).
+					Msg("Could not parse validator min self delegation")
+			} else {
+				metrics.minSelfDelegationGauge.With(prometheus.Labels{
+					"address": validator.OperatorAddress,
+					"moniker": validator.Description.Moniker,
+					"denom":   config.Denom,
+				}).Set(value)
+			}
+
+			if served && config.SelfDelegation && (config.MetricEnabled("cosmos_validators_self_delegation") || config.MetricEnabled("cosmos_validators_delegation_leverage")) {
+				wg.Add(1)
+				go func(validator stakingtypes.Validator) {
+					defer wg.Done()
+
+					selfDelegation, err := s.getSelfDelegation(validator)
+					if err != nil {
+						sublogger.Error().
+							Str("address", validator.OperatorAddress).
+							Err(err).
+							Msg("Could not get validator self delegation")
+						return
+					}
+
+					// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+					if value, err := strconv.ParseFloat(selfDelegation.String(), 64); err != nil {
+						sublogger.Error().
+							Str("address", validator.OperatorAddress).
+							Err(err).
+							Msg("Could not parse validator self delegation")
+					} else {
+						metrics.selfDelegationGauge.With(prometheus.Labels{
+							"address": validator.OperatorAddress,
+							"moniker": validator.Description.Moniker,
+							"denom":   config.Denom,
+						}).Set(value / denomCoefficient)
+					}
+
+					// not served without a self delegation, as it would be infinite
+					if selfDelegation.IsPositive() {
+						externalDelegations := sdk.NewDecFromInt(validator.Tokens.Sub(selfDelegation))
+						leverage, err := DecToFloat64(externalDelegations.QuoInt(selfDelegation))
+						if err != nil {
+							sublogger.Error().
+								Str("address", validator.OperatorAddress).
+								Err(err).
+								Msg("Could not parse validator delegation leverage")
+						} else {
+							metrics.delegationLeverageGauge.With(prometheus.Labels{
+								"address": validator.OperatorAddress,
+								"moniker": validator.Description.Moniker,
+							}).Set(leverage)
+						}
+					}
+				}(validator)
+			}
+
+			if config.UnbondingEntries {
+				// all of them are queried, for the count to not depend on --validators-top-n
+				wg.Add(1)
+				go func(validator stakingtypes.Validator, served bool) {
+					defer wg.Done()
+
+					entries, err := s.getUnbondingEntries(validator.OperatorAddress)
+					if err != nil {
+						sublogger.Error().
+							Str("address", validator.OperatorAddress).
+							Err(err).
+							Msg("Could not get validator unbonding delegations")
+						return
+					}
+
+					if entries > 0 {
+						withUnbonding.Add(1)
+					}
+					if served {
+						metrics.unbondingEntriesGauge.With(prometheus.Labels{
+							"address": validator.OperatorAddress,
+							"moniker": validator.Description.Moniker,
+						}).Set(float64(entries))
+					}
+				}(validator, served)
+			}
 		}
 
-		err = validator.UnpackInterfaces(interfaceRegistry) // Unpack interfaces, to populate the Anys' cached values
+		err := validator.UnpackInterfaces(interfaceRegistry) // Unpack interfaces, to populate the Anys' cached values
 		if err != nil {
 			sublogger.Error().
 				Str("address", validator.OperatorAddress).
@@ -321,7 +844,7 @@ func (s *Service) ValidatorsHandler(w http.ResponseWriter, r *http.Request) {
 				Msg("Could not get unpack validator inferfaces")
 		}
 
-		pubKey, err := validator.GetConsAddr()
+		pubKey, err := ConsAddress(validator)
 		if err != nil {
 			sublogger.Error().
 				Str("address", validator.OperatorAddress).
@@ -329,76 +852,614 @@ func (s *Service) ValidatorsHandler(w http.ResponseWriter, r *http.Request) {
 				Msg("Could not get validator pubkey")
 		}
 
+		// an empty address would match any signing info indexed under it
 		var signingInfo slashingtypes.ValidatorSigningInfo
-		found := false
+		var found bool
+		if len(pubKey) > 0 {
+			signingInfo, found = signingInfosByAddress[string(pubKey)]
+		}
 
-		for _, signingInfoIterated := range signingInfos {
-			if pubKey.String() == signingInfoIterated.Address {
-				found = true
-				signingInfo = signingInfoIterated
-				break
-			}
+		if !found && validator.Status == stakingtypes.Bonded {
+			// a non zero count means --limit is too low for the chain size
+			missingSigningInfos++
 		}
 
-		if !found {
-			slashingClient := slashingtypes.NewQueryClient(s.GrpcConn)
-			slashingRes, err := slashingClient.SigningInfo(
-				context.Background(),
-				&slashingtypes.QuerySigningInfoRequest{ConsAddress: pubKey.String()},
-			)
-			if err != nil {
+		if !found && served && len(pubKey) > 0 {
+			signingInfoFallbacks++
+			fallbackSigningInfo, err := s.getFallbackSigningInfo(pubKey)
+			if status.Code(err) == codes.DeadlineExceeded {
+				signingInfoFallbackTimeouts++
+				sublogger.Warn().
+					Str("address", validator.OperatorAddress).
+					Dur("timeout", config.FallbackQueryTimeout).
+					Msg("Signing info query for validator timed out")
+			} else if err != nil {
+				// pruned nodes may not have it, the other metrics of the validator are still served
 				sublogger.Debug().
 					Str("address", validator.OperatorAddress).
 					Msg("Could not get signing info for validator")
-				continue
+			} else {
+				found = true
+				signingInfo = fallbackSigningInfo
+			}
+		}
+
+		if validator.Status == stakingtypes.Bonded {
+			// golang doesn't have a ternary operator, so we have to stick with this ugly solution
+			var signingInfoAvailable float64
+
+			if found {
+				signingInfoAvailable = 1
+				bondedSigningInfos++
+			} else {
+				signingInfoAvailable = 0
+			}
+			metrics.signingInfoAvailableGauge.With(prometheus.Labels{
+				"address": validator.OperatorAddress,
+				"moniker": validator.Description.Moniker,
+			}).Set(signingInfoAvailable)
+		}
+
+		// the jailing end is only in the signing info, without it we can't tell
+		if found {
+			// golang doesn't have a ternary operator, so we have to stick with this ugly solution
+			var unjailableNow float64
+
+			if Unjailable(validator, signingInfo, time.Now()) {
+				unjailableNow = 1
+			} else {
+				unjailableNow = 0
 			}
-			found = true
-			signingInfo = slashingRes.ValSigningInfo
+			metrics.unjailableNowGauge.With(prometheus.Labels{
+				"address": validator.OperatorAddress,
+				"moniker": validator.Description.Moniker,
+			}).Set(unjailableNow)
 		}
 
-		if found && (validator.Status == stakingtypes.Bonded) {
-			validatorsMissedBlocksGauge.With(prometheus.Labels{
+		// the signing infos of the jailed and unbonding validators still exist, but their missed blocks aren't served
+		// by default as the dashboards used to only get the active set ones
+		if found && (validator.Status == stakingtypes.Bonded || config.UnbondedMissed) {
+			metrics.missedBlocksGauge.With(prometheus.Labels{
 				"address": validator.OperatorAddress,
 				"moniker": validator.Description.Moniker,
 			}).Set(float64(signingInfo.MissedBlocksCounter))
+
+			if resets, ok := s.updateSigningWindowResets(validator.OperatorAddress, signingInfo.MissedBlocksCounter); ok {
+				metrics.signingWindowResetsCounter.With(prometheus.Labels{
+					"address": validator.OperatorAddress,
+					"moniker": validator.Description.Moniker,
+				}).Add(resets)
+			}
+
+			if signedBlocksWindow > 0 {
+				metrics.missedRatioGauge.With(prometheus.Labels{
+					"address": validator.OperatorAddress,
+					"moniker": validator.Description.Moniker,
+				}).Set(float64(signingInfo.MissedBlocksCounter) / float64(signedBlocksWindow))
+			}
 		} else {
 			sublogger.Trace().
 				Str("address", validator.OperatorAddress).
 				Msg("Validator is not active, not returning missed blocks amount.")
 		}
 
-		validatorsRankGauge.With(prometheus.Labels{
-			"address": validator.OperatorAddress,
-			"moniker": validator.Description.Moniker,
-		}).Set(float64(index + 1))
+		// the validators out of --rank-min and --rank-max go through the loop like the others, only their ranks aren't served
+		if !minimal && config.InRankBand(index+1) {
+			metrics.rankGauge.With(prometheus.Labels{
+				"address": validator.OperatorAddress,
+				"moniker": validator.Description.Moniker,
+			}).Set(float64(index + 1))
+
+			// bonded validators are sorted first, so counting them gives the rank explorers show
+			if validator.Status == stakingtypes.Bonded {
+				metrics.activeRankGauge.With(prometheus.Labels{
+					"address": validator.OperatorAddress,
+					"moniker": validator.Description.Moniker,
+				}).Set(float64(bondedValidators))
+			}
+
+			if previousRank, ok := previousRanks[validator.OperatorAddress]; ok {
+				metrics.rankDeltaGauge.With(prometheus.Labels{
+					"address": validator.OperatorAddress,
+					"moniker": validator.Description.Moniker,
+				}).Set(float64(previousRank - (index + 1)))
+			}
+		}
+
+		if !minimal {
+			if since, ok := bondedSince[validator.OperatorAddress]; ok {
+				metrics.bondedSecondsGauge.With(prometheus.Labels{
+					"address": validator.OperatorAddress,
+					"moniker": validator.Description.Moniker,
+				}).Set(time.Since(since).Seconds())
+			}
 
-		if validatorSetLength != 0 {
 			// golang doesn't have a ternary operator, so we have to stick with this ugly solution
-			active := float64(1)
+			var monikerCollision float64
 
-			if validator.Jailed {
-				active = 0
+			if monikers[normalizeMoniker(validator.Description.Moniker)] > 1 {
+				monikerCollision = 1
+			} else {
+				monikerCollision = 0
 			}
+			metrics.monikerCollisionGauge.With(prometheus.Labels{
+				"address": validator.OperatorAddress,
+				"moniker": validator.Description.Moniker,
+			}).Set(monikerCollision)
+		}
+
+		if validatorSetLength != 0 {
+			// golang doesn't have a ternary operator, so we have to stick with this ugly solution
+			var active float64
 
-			if activeValidators == int(validatorSetLength) {
+			// the fallback signing infos aren't known before the loop, their tombstoned validators still take a slot
+			if activeSet[index] && !(found && signingInfo.Tombstoned) {
+				active = 1
+				activeValidators++
+			} else {
 				active = 0
 			}
-			activeValidators += int(active)
 
-			validatorsIsActiveGauge.With(prometheus.Labels{
+			metrics.isActiveGauge.With(prometheus.Labels{
 				"address":     validator.OperatorAddress,
 				"moniker":     validator.Description.Moniker,
 				"pubkey_hash": strings.ToUpper(hex.EncodeToString(pubKey.Bytes())),
 			}).Set(active)
 		}
 	}
-	sublogger.Info().Int("activeValidators", activeValidators).Msg("Active validators")
+	sublogger.Info().
+		Int("activeValidators", activeValidators).
+		Int("missingSigningInfos", missingSigningInfos).
+		Msg("Active validators")
+	metrics.missingSigningInfosGauge.Set(float64(missingSigningInfos))
+	// pruned nodes or a truncated signing infos query, not set without bonded validators as there is nothing to cover
+	if bondedValidators != 0 {
+		metrics.signingCoverageGauge.Set(float64(bondedSigningInfos) / float64(bondedValidators))
+	}
+	// compare it with the validators in the explorers to spot a truncated set
+	metrics.fetchedGauge.Set(float64(len(validators)))
+	metrics.signingInfoFallbackTimeoutsGauge.Set(float64(signingInfoFallbackTimeouts))
+	// a spike across the network points at a chain-wide issue, like a bad release
+	metrics.jailedCountGauge.Set(float64(jailedValidators))
+	// when all the slots are used, new validators can only join by displacing one
+	metrics.slotsUsedGauge.Set(float64(bondedValidators))
+	if validatorSetLength != 0 {
+		metrics.slotsTotalGauge.Set(float64(validatorSetLength))
+	}
+
+	// waiting for the per-validator queries spawned in the loop above
+	wg.Wait()
+	metrics.withUnbondingGauge.Set(float64(withUnbonding.Load()))
+
+	for _, address := range notServed {
+		for _, vec := range []*prometheus.MetricVec{
+			metrics.commissionGauge.MetricVec,
+			metrics.commissionVsAvgGauge.MetricVec,
+			metrics.commissionBpsGauge.MetricVec,
+			metrics.commissionUpdateTimeGauge.MetricVec,
+			metrics.belowMinCommissionGauge.MetricVec,
+			metrics.statusGauge.MetricVec,
+			metrics.jailedGauge.MetricVec,
+			metrics.unjailableNowGauge.MetricVec,
+			metrics.tokensGauge.MetricVec,
+			metrics.votingPowerGauge.MetricVec,
+			metrics.delegationInflowCounter.MetricVec,
+			metrics.delegationOutflowCounter.MetricVec,
+			metrics.delegatorSharesGauge.MetricVec,
+			metrics.minSelfDelegationGauge.MetricVec,
+			metrics.missedBlocksGauge.MetricVec,
+			metrics.signingInfoAvailableGauge.MetricVec,
+			metrics.signingWindowResetsCounter.MetricVec,
+			metrics.unjailEventsCounter.MetricVec,
+			metrics.missedRatioGauge.MetricVec,
+			metrics.rankGauge.MetricVec,
+			metrics.activeRankGauge.MetricVec,
+			metrics.rankDeltaGauge.MetricVec,
+			metrics.bondedSecondsGauge.MetricVec,
+			metrics.monikerCollisionGauge.MetricVec,
+			metrics.isActiveGauge.MetricVec,
+		} {
+			vec.DeletePartialMatch(prometheus.Labels{"address": address})
+		}
+	}
 
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	h.ServeHTTP(w, r)
+	s.ServeMetrics(w, r, registry)
 	sublogger.Info().
 		Str("method", "GET").
 		Str("endpoint", "/metrics/validators").
 		Float64("request-time", time.Since(requestStart).Seconds()).
+		Float64("validators-request-time", validatorsQueryTime.Seconds()).
+		Float64("signing-infos-request-time", signingInfosQueryTime.Seconds()).
+		Float64("staking-params-request-time", stakingParamsQueryTime.Seconds()).
+		Float64("slashing-params-request-time", slashingParamsQueryTime.Seconds()).
+		Int("signing-info-fallbacks", signingInfoFallbacks).
+		Int("signing-info-fallback-timeouts", signingInfoFallbackTimeouts).
 		Msg("Request processed")
 }
+
+// SortValidators sorts the validators by delegator shares to display rankings (unbonded go last)
+func SortValidators(validators []stakingtypes.Validator) {
+	sort.Slice(validators, func(i, j int) bool {
+		if !validators[i].IsBonded() && validators[j].IsBonded() {
+			return false
+		} else if validators[i].IsBonded() && !validators[j].IsBonded() {
+			return true
+		}
+
+		return delegatorShares(validators[i]).Cmp(delegatorShares(validators[j])) > 0
+	})
+}
+
+// delegatorShares returns the delegator shares of the validator, 0 if they're missing from a malformed response,
+// as the nil dec's BigInt is nil and comparing it panics
+func delegatorShares(validator stakingtypes.Validator) *big.Int {
+	if validator.DelegatorShares.IsNil() {
+		return big.NewInt(0)
+	}
+	return validator.DelegatorShares.BigInt()
+}
+
+// IndexSigningInfos indexes the signing infos by the bytes of their consensus address, so they match the validators
+// whether the node returns them as hex or as bech32 with a prefix differing from the configured one
+func IndexSigningInfos(signingInfos []slashingtypes.ValidatorSigningInfo) map[string]slashingtypes.ValidatorSigningInfo {
+	signingInfosByAddress := make(map[string]slashingtypes.ValidatorSigningInfo, len(signingInfos))
+	for _, signingInfo := range signingInfos {
+		address, err := ParseConsAddress(signingInfo.Address)
+		if err != nil {
+			continue
+		}
+		signingInfosByAddress[string(address)] = signingInfo
+	}
+	return signingInfosByAddress
+}
+
+// ConsAddress returns the consensus address of the validator, erroring out instead of panicking like GetConsAddr
+// for the validators without a consensus pubkey, and on an empty address which would match the signing infos without one
+func ConsAddress(validator stakingtypes.Validator) (sdk.ConsAddress, error) {
+	if validator.ConsensusPubkey == nil {
+		return nil, fmt.Errorf("validator %s has no consensus pubkey", validator.OperatorAddress)
+	}
+	address, err := validator.GetConsAddr()
+	if err != nil {
+		return nil, err
+	}
+	if len(address) == 0 {
+		return nil, fmt.Errorf("validator %s has an empty consensus address", validator.OperatorAddress)
+	}
+	return address, nil
+}
+
+// ParseConsAddress parses a consensus address, either bech32 with any prefix or hex, and errors out on an empty one
+func ParseConsAddress(address string) (sdk.ConsAddress, error) {
+	if address == "" {
+		return nil, fmt.Errorf("empty consensus address")
+	}
+	if _, bytes, err := bech32.DecodeAndConvert(address); err == nil {
+		return bytes, nil
+	}
+	return hex.DecodeString(address)
+}
+
+// InRankBand returns whether the rank, counted from 1, is within --rank-min and --rank-max, which are unbounded when 0
+func (config *ServiceConfig) InRankBand(rank int) bool {
+	return (config.RankMin <= 0 || rank >= config.RankMin) && (config.RankMax <= 0 || rank <= config.RankMax)
+}
+
+// ServeValidator returns false for the validators past --validators-top-n in the sorted set,
+// except the ones passed with --validators which are always served
+func (config *ServiceConfig) ServeValidator(index int, address string) bool {
+	if config.ValidatorTopN <= 0 || index < config.ValidatorTopN {
+		return true
+	}
+	for _, validator := range config.Validators {
+		if validator == address {
+			return true
+		}
+	}
+	return false
+}
+
+// swapValidatorRanks stores the ranks of the sorted validators for the next scrape and returns the ones of the previous scrape
+func (s *Service) swapValidatorRanks(validators []stakingtypes.Validator) map[string]int {
+	s.validatorRanksMutex.Lock()
+	defer s.validatorRanksMutex.Unlock()
+
+	previousRanks := s.validatorRanks
+	if len(validators) == 0 {
+		// the query failed, keep the baseline for the next scrape
+		return previousRanks
+	}
+
+	s.validatorRanks = make(map[string]int, len(validators))
+	for index, validator := range validators {
+		s.validatorRanks[validator.OperatorAddress] = index + 1
+	}
+	return previousRanks
+}
+
+// updateBondedSince records when the bonded validators were first seen bonded, forgetting the ones which left the set,
+// and returns the times of the currently bonded ones
+func (s *Service) updateBondedSince(validators []stakingtypes.Validator) map[string]time.Time {
+	s.bondedSinceMutex.Lock()
+	defer s.bondedSinceMutex.Unlock()
+
+	if len(validators) == 0 {
+		// the query failed, don't reset the validators which are still bonded
+		return nil
+	}
+
+	now := time.Now()
+	bondedSince := make(map[string]time.Time, len(validators))
+	for _, validator := range validators {
+		if validator.Status != stakingtypes.Bonded {
+			continue
+		}
+		if since, ok := s.bondedSince[validator.OperatorAddress]; ok {
+			bondedSince[validator.OperatorAddress] = since
+		} else {
+			bondedSince[validator.OperatorAddress] = now
+		}
+	}
+	s.bondedSince = bondedSince
+	return bondedSince
+}
+
+// missedBlocks is the missed blocks counter of a validator in the previous scrape and the times it went down
+type missedBlocks struct {
+	counter int64
+	resets  float64
+}
+
+// updateSigningWindowResets counts a reset of the signing window when the missed blocks counter went down since the previous scrape
+// and returns the resets, ok is false on the first sample as there is nothing to compare it with yet
+func (s *Service) updateSigningWindowResets(address string, counter int64) (resets float64, ok bool) {
+	s.missedBlocksMutex.Lock()
+	defer s.missedBlocksMutex.Unlock()
+
+	if s.missedBlocks == nil {
+		s.missedBlocks = make(map[string]*missedBlocks)
+	}
+
+	previous, ok := s.missedBlocks[address]
+	if !ok {
+		s.missedBlocks[address] = &missedBlocks{counter: counter}
+		return 0, false
+	}
+
+	if counter < previous.counter {
+		previous.resets++
+	}
+	previous.counter = counter
+	return previous.resets, true
+}
+
+// getFallbackSigningInfo queries the signing info of a validator missing from the bulk query, within --fallback-query-timeout
+// as they're queried one after the other, so a slow node would make them take the whole scrape
+func (s *Service) getFallbackSigningInfo(consAddress sdk.ConsAddress) (slashingtypes.ValidatorSigningInfo, error) {
+	ctx := context.Background()
+	if s.Config.FallbackQueryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Config.FallbackQueryTimeout)
+		defer cancel()
+	}
+
+	slashingClient := slashingtypes.NewQueryClient(s.GrpcConn)
+	slashingRes, err := slashingClient.SigningInfo(
+		ctx,
+		&slashingtypes.QuerySigningInfoRequest{ConsAddress: consAddress.String()},
+	)
+	if err != nil {
+		return slashingtypes.ValidatorSigningInfo{}, err
+	}
+	return slashingRes.ValSigningInfo, nil
+}
+
+// ActiveSet returns whether each of the validators, sorted with SortValidators, is in the active set of maxValidators:
+// the first ones which aren't jailed nor tombstoned, by operator address, until the set is full. The tombstoned ones
+// are permanently removed, whatever their status. When there are fewer validators than maxValidators, all the ones
+// which aren't jailed nor tombstoned are active. The counts are int64, so a huge MaxValidators isn't truncated.
+func ActiveSet(validators []stakingtypes.Validator, maxValidators uint32, tombstoned map[string]bool) []bool {
+	activeSet := make([]bool, len(validators))
+	var active int64
+	for index, validator := range validators {
+		if validator.Jailed || tombstoned[validator.OperatorAddress] || active >= int64(maxValidators) {
+			continue
+		}
+		activeSet[index] = true
+		active++
+	}
+	return activeSet
+}
+
+// Unjailable returns whether an unjail transaction of the validator would succeed at now: it is jailed, its jailing
+// period is over and it isn't tombstoned, as the tombstoned ones stay jailed forever
+func Unjailable(validator stakingtypes.Validator, signingInfo slashingtypes.ValidatorSigningInfo, now time.Time) bool {
+	return validator.Jailed && !signingInfo.Tombstoned && !now.Before(signingInfo.JailedUntil)
+}
+
+// AverageBondedCommission returns the average commission of the bonded validators, computed with the decs so it does
+// not add up the float artifacts of every commission, and false if there are none
+func AverageBondedCommission(validators []stakingtypes.Validator) (sdk.Dec, bool) {
+	total := sdk.ZeroDec()
+	bonded := int64(0)
+	for _, validator := range validators {
+		if validator.Status != stakingtypes.Bonded || validator.Commission.CommissionRates.Rate.IsNil() {
+			continue
+		}
+		total = total.Add(validator.Commission.CommissionRates.Rate)
+		bonded++
+	}
+	if bonded == 0 {
+		return sdk.Dec{}, false
+	}
+	return total.QuoInt64(bonded), true
+}
+
+// tombstonedValidators returns the operator addresses of the validators tombstoned in the bulk signing infos,
+// the errors of their pubkeys are logged when going through them afterwards
+func tombstonedValidators(validators []stakingtypes.Validator, signingInfosByAddress map[string]slashingtypes.ValidatorSigningInfo, interfaceRegistry codectypes.InterfaceRegistry) map[string]bool {
+	tombstoned := make(map[string]bool)
+	for _, validator := range validators {
+		if err := validator.UnpackInterfaces(interfaceRegistry); err != nil {
+			continue
+		}
+		consAddress, err := ConsAddress(validator)
+		if err != nil {
+			continue
+		}
+		if signingInfo, found := signingInfosByAddress[string(consAddress)]; found && signingInfo.Tombstoned {
+			tombstoned[validator.OperatorAddress] = true
+		}
+	}
+	return tombstoned
+}
+
+// normalizeMoniker returns the moniker as compared for the collisions, case and space insensitive
+func normalizeMoniker(moniker string) string {
+	return strings.ToLower(strings.Join(strings.Fields(moniker), " "))
+}
+
+// MinimalScrape returns whether the minimal query param is set, like /metrics/validators?minimal,
+// to only serve the status, jailed and missed blocks metrics of the validators, skipping the heavier ones and their parsing
+func MinimalScrape(r *http.Request) bool {
+	values, ok := r.URL.Query()["minimal"]
+	return ok && (len(values) == 0 || values[0] != "false")
+}
+
+// denomCoefficient returns --denom-coefficient, or 1 if it's 0 as it would make every tokens gauge +Inf,
+// warning about it once. Validate rejects it at startup, this is in case the config was built another way.
+func (s *Service) denomCoefficient(sublogger *zerolog.Logger) float64 {
+	if s.Config.DenomCoefficient != 0 {
+		return s.Config.DenomCoefficient
+	}
+
+	s.zeroCoefficientWarning.Do(func() {
+		sublogger.Warn().Msg("Denom coefficient is 0, using 1 instead")
+	})
+	return 1
+}
+
+// jailState is whether a validator was jailed in the previous scrape, since when, and the times it was unjailed
+type jailState struct {
+	jailed       bool
+	jailedSince  time.Time
+	unjailEvents float64
+}
+
+// updateUnjailEvents counts an unjail event when the validator was jailed in the previous scrape and isn't anymore,
+// and returns the events. jailedFor is how long it was seen jailed if it was just unjailed, so at most a scrape interval
+// short of the real downtime, and ok is false on the first sample as there is nothing to compare it with yet
+func (s *Service) updateUnjailEvents(address string, jailed bool) (events float64, jailedFor time.Duration, ok bool) {
+	s.jailStatesMutex.Lock()
+	defer s.jailStatesMutex.Unlock()
+
+	if s.jailStates == nil {
+		s.jailStates = make(map[string]*jailState)
+	}
+
+	now := time.Now()
+	previous, ok := s.jailStates[address]
+	if !ok {
+		s.jailStates[address] = &jailState{jailed: jailed, jailedSince: now}
+		return 0, 0, false
+	}
+
+	switch {
+	case previous.jailed && !jailed:
+		previous.unjailEvents++
+		jailedFor = now.Sub(previous.jailedSince)
+	case !previous.jailed && jailed:
+		previous.jailedSince = now
+	}
+	previous.jailed = jailed
+	return previous.unjailEvents, jailedFor, true
+}
+
+// stakeFlow accumulates the token changes of a validator between scrapes, split by sign
+type stakeFlow struct {
+	tokens  float64
+	inflow  float64
+	outflow float64
+}
+
+// updateStakeFlow adds the token change since the previous scrape to the validator flows and returns them,
+// ok is false on the first sample as there is nothing to compare it with yet
+func (s *Service) updateStakeFlow(address string, tokens float64) (flow stakeFlow, ok bool) {
+	s.stakeFlowsMutex.Lock()
+	defer s.stakeFlowsMutex.Unlock()
+
+	if s.stakeFlows == nil {
+		s.stakeFlows = make(map[string]*stakeFlow)
+	}
+
+	previous, ok := s.stakeFlows[address]
+	if !ok {
+		s.stakeFlows[address] = &stakeFlow{tokens: tokens}
+		return stakeFlow{}, false
+	}
+
+	if delta := tokens - previous.tokens; delta > 0 {
+		previous.inflow += delta
+	} else {
+		previous.outflow -= delta
+	}
+	previous.tokens = tokens
+	return *previous, true
+}
+
+// getUnbondingEntries returns the number of unbonding delegation entries still pending on the validator
+func (s *Service) getUnbondingEntries(address string) (int, error) {
+	stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
+
+	unbondings, err := Paginate(func(key []byte) ([]stakingtypes.UnbondingDelegation, []byte, error) {
+		unbondingsResponse, err := stakingClient.ValidatorUnbondingDelegations(
+			context.Background(),
+			&stakingtypes.QueryValidatorUnbondingDelegationsRequest{
+				ValidatorAddr: address,
+				Pagination: &querytypes.PageRequest{
+					Key:   key,
+					Limit: s.Config.Limit,
+				},
+			},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		return unbondingsResponse.UnbondingResponses, unbondingsResponse.Pagination.GetNextKey(), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	entries := 0
+	for _, unbonding := range unbondings {
+		entries += len(unbonding.Entries)
+	}
+	return entries, nil
+}
+
+// getSelfDelegation returns the tokens the validator operator account has delegated to its own validator
+func (s *Service) getSelfDelegation(validator stakingtypes.Validator) (sdk.Int, error) {
+	valAddress, err := sdk.ValAddressFromBech32(validator.OperatorAddress)
+	if err != nil {
+		return sdk.Int{}, err
+	}
+
+	stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
+	delegationResponse, err := stakingClient.Delegation(
+		context.Background(),
+		&stakingtypes.QueryDelegationRequest{
+			DelegatorAddr: sdk.AccAddress(valAddress).String(),
+			ValidatorAddr: validator.OperatorAddress,
+		},
+	)
+	if status.Code(err) == codes.NotFound {
+		// the operator has no delegation left on its own validator
+		return sdk.ZeroInt(), nil
+	}
+	if err != nil {
+		return sdk.Int{}, err
+	}
+
+	return delegationResponse.DelegationResponse.Balance.Amount, nil
+}
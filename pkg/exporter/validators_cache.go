@@ -0,0 +1,102 @@
+package exporter
+
+import (
+	"context"
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"net/http"
+	"time"
+)
+
+// prewarmRetryInterval is how long PrewarmCache waits before trying again after a failed query
+const prewarmRetryInterval = 5 * time.Second
+
+// validatorsCache is the last validators set queried by /metrics/validators, kept for --validators-cache-ttl
+type validatorsCache struct {
+	validators []stakingtypes.Validator
+	fetched    time.Time
+}
+
+// GetValidators returns all the validators, from the cache if it's younger than --validators-cache-ttl.
+// The returned slice is a copy, so the caller can sort it.
+func (s *Service) GetValidators() ([]stakingtypes.Validator, error) {
+	s.validatorsCacheMutex.Lock()
+	defer s.validatorsCacheMutex.Unlock()
+
+	if s.Config.ValidatorsCacheTTL <= 0 || time.Since(s.validatorsCache.fetched) >= s.Config.ValidatorsCacheTTL {
+		validators, err := s.queryValidators()
+		if err != nil {
+			return nil, err
+		}
+		s.validatorsCache = validatorsCache{validators: validators, fetched: time.Now()}
+	}
+
+	return append([]stakingtypes.Validator(nil), s.validatorsCache.validators...), nil
+}
+
+func (s *Service) queryValidators() ([]stakingtypes.Validator, error) {
+	stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
+
+	fullPages := 0
+	validators, err := Paginate(func(key []byte) ([]stakingtypes.Validator, []byte, error) {
+		validatorsResponse, err := stakingClient.Validators(
+			context.Background(),
+			&stakingtypes.QueryValidatorsRequest{
+				Pagination: &querytypes.PageRequest{
+					Key:   key,
+					Limit: s.Config.Limit,
+				},
+			},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// a full page is expected before the last one, but it's also what a truncated set looks like
+		if uint64(len(validatorsResponse.Validators)) == s.Config.Limit {
+			fullPages++
+		}
+		return validatorsResponse.Validators, validatorsResponse.Pagination.GetNextKey(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if fullPages > 0 {
+		s.Log.Warn().
+			Uint64("limit", s.Config.Limit).
+			Int("full-pages", fullPages).
+			Int("validatorsLength", len(validators)).
+			Msg("Validators pages returned exactly --limit entries, the validators may be truncated, consider raising --limit")
+	}
+	return validators, nil
+}
+
+// PrewarmCache fills the validators cache, retrying until it succeeds, so the first scrape
+// doesn't pay the full latency. /ready answers 503 until it's done.
+func (s *Service) PrewarmCache() {
+	for {
+		queryStart := time.Now()
+		validators, err := s.GetValidators()
+		if err == nil {
+			s.warmed.Store(true)
+			s.Log.Info().
+				Float64("request-time", time.Since(queryStart).Seconds()).
+				Int("validatorsLength", len(validators)).
+				Msg("Finished prewarming validators cache")
+			return
+		}
+
+		s.Log.Error().Err(err).Msg("Could not prewarm validators cache, retrying")
+		time.Sleep(prewarmRetryInterval)
+	}
+}
+
+// ReadyHandler answers 503 until the cache is prewarmed when --prewarm-cache is set, and 200 otherwise
+func (s *Service) ReadyHandler(w http.ResponseWriter, _ *http.Request) {
+	if s.Config.PrewarmCache && !s.warmed.Load() {
+		http.Error(w, "cache is not warm yet", http.StatusServiceUnavailable)
+		return
+	}
+	_, _ = w.Write([]byte("ok\n"))
+}
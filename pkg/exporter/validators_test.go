@@ -0,0 +1,434 @@
+package exporter_test
+
+import (
+	"context"
+	"encoding/hex"
+	"main/pkg/exporter"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestIndexSigningInfosMismatchedFormats(t *testing.T) {
+	bech32Address := sdk.ConsAddress([]byte("validator-bech32-addr"))
+	hexAddress := sdk.ConsAddress([]byte("validator-hex-address"))
+	otherPrefixAddress := sdk.ConsAddress([]byte("validator-other-prefix"))
+
+	otherPrefix, err := bech32.ConvertAndEncode("osmovalcons", otherPrefixAddress)
+	require.NoError(t, err)
+
+	signingInfosByAddress := exporter.IndexSigningInfos([]slashingtypes.ValidatorSigningInfo{
+		{Address: bech32Address.String(), MissedBlocksCounter: 1},
+		{Address: strings.ToUpper(hex.EncodeToString(hexAddress)), MissedBlocksCounter: 2},
+		{Address: otherPrefix, MissedBlocksCounter: 3},
+		{Address: "not an address", MissedBlocksCounter: 4},
+		{Address: "", MissedBlocksCounter: 5},
+	})
+	require.Len(t, signingInfosByAddress, 3)
+	require.NotContains(t, signingInfosByAddress, "")
+
+	for address, missedBlocks := range map[string]int64{
+		string(bech32Address):      1,
+		string(hexAddress):         2,
+		string(otherPrefixAddress): 3,
+	} {
+		signingInfo, found := signingInfosByAddress[address]
+		require.True(t, found)
+		require.Equal(t, missedBlocks, signingInfo.MissedBlocksCounter)
+	}
+}
+
+func TestParseConsAddressEmpty(t *testing.T) {
+	_, err := exporter.ParseConsAddress("")
+	require.Error(t, err)
+}
+
+// noPubkeyStakingServer serves a validator without a consensus pubkey, so it has no consensus address
+type noPubkeyStakingServer struct {
+	emptyStakingServer
+}
+
+func (noPubkeyStakingServer) Validators(context.Context, *stakingtypes.QueryValidatorsRequest) (*stakingtypes.QueryValidatorsResponse, error) {
+	return &stakingtypes.QueryValidatorsResponse{Validators: []stakingtypes.Validator{{
+		OperatorAddress: sdk.ValAddress("validator").String(),
+		Status:          stakingtypes.Bonded,
+		Tokens:          sdk.NewInt(1000000),
+		DelegatorShares: sdk.NewDec(1000000),
+		Description:     stakingtypes.Description{Moniker: "validator"},
+	}}}, nil
+}
+
+// emptyAddressSlashingServer returns a signing info without an address
+type emptyAddressSlashingServer struct {
+	emptySlashingServer
+}
+
+func (emptyAddressSlashingServer) SigningInfos(context.Context, *slashingtypes.QuerySigningInfosRequest) (*slashingtypes.QuerySigningInfosResponse, error) {
+	return &slashingtypes.QuerySigningInfosResponse{Info: []slashingtypes.ValidatorSigningInfo{{MissedBlocksCounter: 42}}}, nil
+}
+
+func TestValidatorsHandlerNoConsensusAddress(t *testing.T) {
+	s := newTestService(t, func(server *grpc.Server) {
+		stakingtypes.RegisterQueryServer(server, &noPubkeyStakingServer{})
+		slashingtypes.RegisterQueryServer(server, &emptyAddressSlashingServer{})
+	}, &exporter.ServiceConfig{Limit: 1000, Denom: "uatom", DenomCoefficient: 1, PowerReduction: 1000000})
+
+	recorder := httptest.NewRecorder()
+	s.ValidatorsHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/validators", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotContains(t, recorder.Body.String(), "cosmos_validators_missed_blocks{")
+	require.Contains(t, recorder.Body.String(), `cosmos_validators_signing_info_available{address="`+sdk.ValAddress("validator").String()+`",moniker="validator"} 0`)
+}
+
+func TestSortValidatorsNilDelegatorShares(t *testing.T) {
+	validators := []stakingtypes.Validator{
+		{OperatorAddress: "unbonded", Status: stakingtypes.Unbonded, DelegatorShares: sdk.NewDec(100)},
+		{OperatorAddress: "nil-shares", Status: stakingtypes.Bonded},
+		{OperatorAddress: "bonded", Status: stakingtypes.Bonded, DelegatorShares: sdk.NewDec(10)},
+	}
+
+	require.NotPanics(t, func() {
+		exporter.SortValidators(validators)
+	})
+	require.Equal(t, "bonded", validators[0].OperatorAddress)
+	require.Equal(t, "nil-shares", validators[1].OperatorAddress)
+	require.Equal(t, "unbonded", validators[2].OperatorAddress)
+}
+
+type emptyStakingServer struct {
+	stakingtypes.UnimplementedQueryServer
+}
+
+func (emptyStakingServer) Validators(context.Context, *stakingtypes.QueryValidatorsRequest) (*stakingtypes.QueryValidatorsResponse, error) {
+	return &stakingtypes.QueryValidatorsResponse{}, nil
+}
+
+func (emptyStakingServer) Params(context.Context, *stakingtypes.QueryParamsRequest) (*stakingtypes.QueryParamsResponse, error) {
+	return &stakingtypes.QueryParamsResponse{Params: stakingtypes.DefaultParams()}, nil
+}
+
+type oneValidatorStakingServer struct {
+	emptyStakingServer
+}
+
+func (oneValidatorStakingServer) Validators(context.Context, *stakingtypes.QueryValidatorsRequest) (*stakingtypes.QueryValidatorsResponse, error) {
+	validator, err := stakingtypes.NewValidator(sdk.ValAddress("validator"), ed25519.GenPrivKey().PubKey(), stakingtypes.Description{Moniker: "validator"})
+	if err != nil {
+		return nil, err
+	}
+	validator.Status = stakingtypes.Bonded
+	validator.Tokens = sdk.NewInt(1000000)
+	validator.DelegatorShares = sdk.NewDec(1000000)
+	return &stakingtypes.QueryValidatorsResponse{Validators: []stakingtypes.Validator{validator}}, nil
+}
+
+// minSelfDelegationStakingServer serves the validator with a large min self delegation
+type minSelfDelegationStakingServer struct {
+	oneValidatorStakingServer
+	minSelfDelegation sdk.Int
+}
+
+func (server minSelfDelegationStakingServer) Validators(ctx context.Context, request *stakingtypes.QueryValidatorsRequest) (*stakingtypes.QueryValidatorsResponse, error) {
+	response, err := server.oneValidatorStakingServer.Validators(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	for index := range response.Validators {
+		response.Validators[index].MinSelfDelegation = server.minSelfDelegation
+	}
+	return response, nil
+}
+
+type emptySlashingServer struct {
+	slashingtypes.UnimplementedQueryServer
+}
+
+func (emptySlashingServer) SigningInfos(context.Context, *slashingtypes.QuerySigningInfosRequest) (*slashingtypes.QuerySigningInfosResponse, error) {
+	return &slashingtypes.QuerySigningInfosResponse{}, nil
+}
+
+func (emptySlashingServer) Params(context.Context, *slashingtypes.QueryParamsRequest) (*slashingtypes.QueryParamsResponse, error) {
+	return &slashingtypes.QueryParamsResponse{Params: slashingtypes.DefaultParams()}, nil
+}
+
+// newValidatorsTestService returns a service querying the staking server, and the empty slashing one
+func newValidatorsTestService(t *testing.T, stakingServer stakingtypes.QueryServer, config *exporter.ServiceConfig) *exporter.Service {
+	t.Helper()
+
+	return newTestService(t, func(server *grpc.Server) {
+		stakingtypes.RegisterQueryServer(server, stakingServer)
+		slashingtypes.RegisterQueryServer(server, &emptySlashingServer{})
+	}, config)
+}
+
+// newTestService returns a service querying the servers registered by register over an in-memory connection
+func newTestService(t *testing.T, register func(server *grpc.Server), config *exporter.ServiceConfig) *exporter.Service {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	s := exporter.NewService()
+	s.GrpcConn = conn
+	s.Config = config
+	s.Log = zerolog.Nop()
+	return s
+}
+
+func TestValidatorsHandlerZeroValidators(t *testing.T) {
+	s := newValidatorsTestService(t, &emptyStakingServer{}, &exporter.ServiceConfig{Limit: 1000, DenomCoefficient: 1})
+
+	recorder := httptest.NewRecorder()
+	s.ValidatorsHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/validators", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "cosmos_validators_jailed_count 0")
+	require.NotContains(t, recorder.Body.String(), "address=")
+}
+
+func TestValidatorsHandlerZeroDenomCoefficient(t *testing.T) {
+	s := newValidatorsTestService(t, &oneValidatorStakingServer{}, &exporter.ServiceConfig{Limit: 1000, Denom: "uatom", PowerReduction: 1000000})
+
+	recorder := httptest.NewRecorder()
+	s.ValidatorsHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/validators", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `cosmos_validators_tokens{address="`+sdk.ValAddress("validator").String()+`",denom="uatom",moniker="validator"} 1e+06`)
+	require.NotContains(t, recorder.Body.String(), "Inf")
+	require.NotContains(t, recorder.Body.String(), "NaN")
+}
+
+func TestValidatorsHandlerMinimal(t *testing.T) {
+	s := newValidatorsTestService(t, &oneValidatorStakingServer{}, &exporter.ServiceConfig{Limit: 1000, Denom: "uatom", DenomCoefficient: 1, PowerReduction: 1000000})
+
+	recorder := httptest.NewRecorder()
+	s.ValidatorsHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/validators?minimal", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "cosmos_validators_jailed{")
+	require.Contains(t, recorder.Body.String(), "cosmos_validators_status{")
+	// the empty slashing server has no signing info for it
+	require.Contains(t, recorder.Body.String(), "cosmos_exporter_signing_coverage 0\n")
+	require.NotContains(t, recorder.Body.String(), "cosmos_validators_tokens")
+	require.NotContains(t, recorder.Body.String(), "cosmos_validators_commission")
+}
+
+func TestValidatorsHandlerMinSelfDelegation18Decimals(t *testing.T) {
+	// parsing it as float64 before dividing by 1e18 rounds it twice, to 695852.350502065
+	minSelfDelegation, ok := sdk.NewIntFromString("695852350502065145676860")
+	require.True(t, ok)
+	s := newValidatorsTestService(t, &minSelfDelegationStakingServer{minSelfDelegation: minSelfDelegation}, &exporter.ServiceConfig{Limit: 1000, Denom: "evmos", DenomCoefficient: 1e18, PowerReduction: 1000000})
+
+	recorder := httptest.NewRecorder()
+	s.ValidatorsHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/validators", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `cosmos_validators_min_self_delegation{address="`+sdk.ValAddress("validator").String()+`",denom="evmos",moniker="validator"} 695852.3505020652`+"\n")
+}
+
+func TestIntToFloat64(t *testing.T) {
+	value, err := exporter.IntToFloat64(sdk.NewInt(1500000), 1000000)
+	require.NoError(t, err)
+	require.Equal(t, 1.5, value)
+
+	_, err = exporter.IntToFloat64(sdk.Int{}, 1000000)
+	require.Error(t, err)
+
+	_, err = exporter.IntToFloat64(sdk.NewInt(1), 0)
+	require.Error(t, err)
+}
+
+func TestRoundDecToFloat64(t *testing.T) {
+	rate := sdk.MustNewDecFromStr("0.049999999999999999")
+
+	value, err := exporter.RoundDecToFloat64(rate, 4)
+	require.NoError(t, err)
+	require.Equal(t, 0.05, value)
+
+	value, err = exporter.RoundDecToFloat64(sdk.MustNewDecFromStr("0.123456"), 2)
+	require.NoError(t, err)
+	require.Equal(t, 0.12, value)
+
+	// full precision by default
+	value, err = exporter.RoundDecToFloat64(sdk.MustNewDecFromStr("0.123456"), 0)
+	require.NoError(t, err)
+	require.Equal(t, 0.123456, value)
+
+	_, err = exporter.RoundDecToFloat64(sdk.Dec{}, 4)
+	require.Error(t, err)
+}
+
+func TestAverageBondedCommission(t *testing.T) {
+	validator := func(status stakingtypes.BondStatus, rate string) stakingtypes.Validator {
+		return stakingtypes.Validator{
+			Status:     status,
+			Commission: stakingtypes.NewCommission(sdk.MustNewDecFromStr(rate), sdk.OneDec(), sdk.ZeroDec()),
+		}
+	}
+
+	// the unbonded ones don't count
+	average, ok := exporter.AverageBondedCommission([]stakingtypes.Validator{
+		validator(stakingtypes.Bonded, "0.05"),
+		validator(stakingtypes.Bonded, "0.10"),
+		validator(stakingtypes.Unbonded, "1"),
+	})
+	require.True(t, ok)
+	require.Equal(t, sdk.MustNewDecFromStr("0.075"), average)
+
+	_, ok = exporter.AverageBondedCommission([]stakingtypes.Validator{validator(stakingtypes.Unbonding, "0.05")})
+	require.False(t, ok)
+}
+
+func TestActiveSet(t *testing.T) {
+	validators := []stakingtypes.Validator{
+		{OperatorAddress: "first", Status: stakingtypes.Bonded},
+		{OperatorAddress: "jailed", Status: stakingtypes.Unbonding, Jailed: true},
+		{OperatorAddress: "second", Status: stakingtypes.Bonded},
+		{OperatorAddress: "third", Status: stakingtypes.Unbonded},
+	}
+
+	require.Equal(t, []bool{true, false, true, false}, exporter.ActiveSet(validators, 2, nil))
+	// more slots than validators: all the ones which aren't jailed are active
+	require.Equal(t, []bool{true, false, true, true}, exporter.ActiveSet(validators, 100, nil))
+	require.Equal(t, []bool{true, false, true, true}, exporter.ActiveSet(validators, math.MaxUint32, nil))
+	require.Equal(t, []bool{false, false, false, false}, exporter.ActiveSet(validators, 0, nil))
+	require.Empty(t, exporter.ActiveSet(nil, 100, nil))
+}
+
+func TestActiveSetTombstoned(t *testing.T) {
+	validators := []stakingtypes.Validator{
+		{OperatorAddress: "first", Status: stakingtypes.Bonded},
+		// still listed as bonded, and not jailed yet, right after the double sign
+		{OperatorAddress: "tombstoned", Status: stakingtypes.Bonded},
+		{OperatorAddress: "second", Status: stakingtypes.Bonded},
+		{OperatorAddress: "third", Status: stakingtypes.Unbonded},
+	}
+
+	tombstoned := map[string]bool{"tombstoned": true}
+	require.Equal(t, []bool{true, false, true, false}, exporter.ActiveSet(validators, 2, tombstoned))
+	require.Equal(t, []bool{true, false, true, true}, exporter.ActiveSet(validators, 100, tombstoned))
+}
+
+// jailedStakingServer serves a bonded validator and a jailed one, ranked second, with their signing infos
+type jailedStakingServer struct {
+	emptyStakingServer
+	bonded, jailed cryptotypes.PubKey
+}
+
+func (server jailedStakingServer) Validators(context.Context, *stakingtypes.QueryValidatorsRequest) (*stakingtypes.QueryValidatorsResponse, error) {
+	bonded, err := stakingtypes.NewValidator(sdk.ValAddress("bonded"), server.bonded, stakingtypes.Description{Moniker: "bonded"})
+	if err != nil {
+		return nil, err
+	}
+	bonded.Status = stakingtypes.Bonded
+	bonded.Tokens = sdk.NewInt(1000000)
+	bonded.DelegatorShares = sdk.NewDec(1000000)
+
+	jailed, err := stakingtypes.NewValidator(sdk.ValAddress("jailed"), server.jailed, stakingtypes.Description{Moniker: "jailed"})
+	if err != nil {
+		return nil, err
+	}
+	jailed.Jailed = true
+	jailed.Tokens = sdk.NewInt(1000)
+	jailed.DelegatorShares = sdk.NewDec(1000)
+	return &stakingtypes.QueryValidatorsResponse{Validators: []stakingtypes.Validator{bonded, jailed}}, nil
+}
+
+type jailedSlashingServer struct {
+	emptySlashingServer
+	bonded, jailed cryptotypes.PubKey
+}
+
+func (server jailedSlashingServer) SigningInfos(context.Context, *slashingtypes.QuerySigningInfosRequest) (*slashingtypes.QuerySigningInfosResponse, error) {
+	return &slashingtypes.QuerySigningInfosResponse{Info: []slashingtypes.ValidatorSigningInfo{
+		{Address: sdk.ConsAddress(server.bonded.Address()).String()},
+		{Address: sdk.ConsAddress(server.jailed.Address()).String(), JailedUntil: time.Now().Add(-time.Hour)},
+	}}, nil
+}
+
+func TestValidatorsHandlerUnjailableNowTopN(t *testing.T) {
+	bonded, jailed := ed25519.GenPrivKey().PubKey(), ed25519.GenPrivKey().PubKey()
+	unjailable := `cosmos_validators_unjailable_now{address="` + sdk.ValAddress("jailed").String() + `",moniker="jailed"} 1`
+
+	for _, test := range []struct {
+		name   string
+		topN   int
+		served bool
+	}{
+		{name: "all validators", topN: 0, served: true},
+		{name: "jailed one out of the top N", topN: 1, served: false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestService(t, func(server *grpc.Server) {
+				stakingtypes.RegisterQueryServer(server, &jailedStakingServer{bonded: bonded, jailed: jailed})
+				slashingtypes.RegisterQueryServer(server, &jailedSlashingServer{bonded: bonded, jailed: jailed})
+			}, &exporter.ServiceConfig{Limit: 1000, Denom: "uatom", DenomCoefficient: 1, PowerReduction: 1000000, ValidatorTopN: test.topN})
+
+			recorder := httptest.NewRecorder()
+			s.ValidatorsHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/validators", nil))
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Contains(t, recorder.Body.String(), `cosmos_validators_unjailable_now{address="`+sdk.ValAddress("bonded").String()+`",moniker="bonded"} 0`)
+			if test.served {
+				require.Contains(t, recorder.Body.String(), unjailable)
+			} else {
+				require.NotContains(t, recorder.Body.String(), `address="`+sdk.ValAddress("jailed").String()+`"`)
+			}
+		})
+	}
+}
+
+func TestUnjailable(t *testing.T) {
+	now := time.Now()
+	jailed := stakingtypes.Validator{OperatorAddress: "jailed", Jailed: true}
+
+	require.True(t, exporter.Unjailable(jailed, slashingtypes.ValidatorSigningInfo{
+		JailedUntil: now.Add(-time.Minute),
+	}, now))
+	require.True(t, exporter.Unjailable(jailed, slashingtypes.ValidatorSigningInfo{
+		JailedUntil: now,
+	}, now))
+
+	// still in the jail window
+	require.False(t, exporter.Unjailable(jailed, slashingtypes.ValidatorSigningInfo{
+		JailedUntil: now.Add(time.Minute),
+	}, now))
+
+	// the tombstoned ones are jailed until the end of times, but don't rely on it
+	require.False(t, exporter.Unjailable(jailed, slashingtypes.ValidatorSigningInfo{
+		JailedUntil: now.Add(-time.Minute),
+		Tombstoned:  true,
+	}, now))
+
+	require.False(t, exporter.Unjailable(stakingtypes.Validator{OperatorAddress: "bonded"}, slashingtypes.ValidatorSigningInfo{
+		JailedUntil: now.Add(-time.Minute),
+	}, now))
+}
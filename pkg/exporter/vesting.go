@@ -0,0 +1,204 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type VestingMetrics struct {
+	lockedGauge   *prometheus.GaugeVec
+	unlockedGauge *prometheus.GaugeVec
+	endTimeGauge  *prometheus.GaugeVec
+}
+
+// VestingSchedule is the part of the vesting accounts we expose, implemented by all their types
+type VestingSchedule interface {
+	GetOriginalVesting() sdk.Coins
+	GetVestingCoins(blockTime time.Time) sdk.Coins
+	GetEndTime() int64
+}
+
+func NewVestingMetrics(reg prometheus.Registerer, config *ServiceConfig) *VestingMetrics {
+	m := &VestingMetrics{
+		lockedGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_vesting_locked",
+				Help:        "Tokens of the vesting account which are still locked",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "type", "denom"},
+		),
+		unlockedGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_vesting_unlocked",
+				Help:        "Tokens of the original vesting of the account which are already unlocked",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "type", "denom"},
+		),
+		endTimeGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_vesting_end_time",
+				Help:        "Time all the tokens of the vesting account are unlocked at, as unix timestamp",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "type"},
+		),
+	}
+	reg.MustRegister(m.lockedGauge)
+	reg.MustRegister(m.unlockedGauge)
+	reg.MustRegister(m.endTimeGauge)
+	return m
+}
+func GetVestingMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *VestingMetrics, s *Service, config *ServiceConfig, address string) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().
+			Str("address", address).
+			Msg("Started querying vesting account")
+		queryStart := time.Now()
+
+		authClient := authtypes.NewQueryClient(s.GrpcConn)
+		accountResponse, err := authClient.Account(
+			context.Background(),
+			&authtypes.QueryAccountRequest{Address: address},
+		)
+		if err != nil {
+			sublogger.Error().
+				Str("address", address).
+				Err(err).
+				Msg("Could not get account")
+			return
+		}
+
+		sublogger.Debug().
+			Str("address", address).
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying vesting account")
+
+		accountType, schedule, err := DecodeVestingAccount(accountResponse.Account)
+		if err != nil {
+			sublogger.Error().
+				Str("address", address).
+				Err(err).
+				Msg("Could not decode vesting account")
+			return
+		}
+
+		locked := schedule.GetVestingCoins(time.Now())
+		for _, original := range schedule.GetOriginalVesting() {
+			lockedAmount := locked.AmountOf(original.Denom)
+
+			// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+			if value, err := strconv.ParseFloat(lockedAmount.String(), 64); err != nil {
+				sublogger.Error().
+					Str("address", address).
+					Err(err).
+					Msg("Could not parse locked tokens")
+			} else {
+				metrics.lockedGauge.With(prometheus.Labels{
+					"address": address,
+					"type":    accountType,
+					"denom":   original.Denom,
+				}).Set(value / config.DenomCoefficient)
+			}
+
+			if value, err := strconv.ParseFloat(original.Amount.Sub(lockedAmount).String(), 64); err != nil {
+				sublogger.Error().
+					Str("address", address).
+					Err(err).
+					Msg("Could not parse unlocked tokens")
+			} else {
+				metrics.unlockedGauge.With(prometheus.Labels{
+					"address": address,
+					"type":    accountType,
+					"denom":   original.Denom,
+				}).Set(value / config.DenomCoefficient)
+			}
+		}
+
+		// permanently locked accounts never end
+		if endTime := schedule.GetEndTime(); endTime != 0 {
+			metrics.endTimeGauge.With(prometheus.Labels{
+				"address": address,
+				"type":    accountType,
+			}).Set(float64(endTime))
+		}
+	}()
+
+}
+
+// DecodeVestingAccount decodes the account returned by the auth module into its vesting account type,
+// returned as the name of the type, like "continuous"
+func DecodeVestingAccount(account *codectypes.Any) (string, VestingSchedule, error) {
+	if account == nil {
+		return "", nil, fmt.Errorf("no account")
+	}
+
+	switch account.TypeUrl {
+	case "/cosmos.vesting.v1beta1.ContinuousVestingAccount":
+		var vestingAccount vestingtypes.ContinuousVestingAccount
+		if err := vestingAccount.Unmarshal(account.Value); err != nil {
+			return "", nil, err
+		}
+		return "continuous", vestingAccount, nil
+	case "/cosmos.vesting.v1beta1.PeriodicVestingAccount":
+		var vestingAccount vestingtypes.PeriodicVestingAccount
+		if err := vestingAccount.Unmarshal(account.Value); err != nil {
+			return "", nil, err
+		}
+		return "periodic", vestingAccount, nil
+	case "/cosmos.vesting.v1beta1.DelayedVestingAccount":
+		var vestingAccount vestingtypes.DelayedVestingAccount
+		if err := vestingAccount.Unmarshal(account.Value); err != nil {
+			return "", nil, err
+		}
+		return "delayed", vestingAccount, nil
+	case "/cosmos.vesting.v1beta1.PermanentLockedAccount":
+		var vestingAccount vestingtypes.PermanentLockedAccount
+		if err := vestingAccount.Unmarshal(account.Value); err != nil {
+			return "", nil, err
+		}
+		return "permanent_locked", vestingAccount, nil
+	default:
+		return "", nil, fmt.Errorf("%s is not a vesting account", account.TypeUrl)
+	}
+}
+func (s *Service) VestingHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/vesting")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	vestingMetrics := NewVestingMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	for _, address := range s.Config.VestingAddresses {
+		GetVestingMetrics(&wg, &sublogger, vestingMetrics, s, s.Config, address)
+	}
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/vesting").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
@@ -9,12 +9,11 @@ import (
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
-	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type WalletMetrics struct {
@@ -102,10 +101,22 @@ func GetWalletMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *Wa
 		bankClient := banktypes.NewQueryClient(s.GrpcConn)
 
 		if allBalances {
-			bankRes, err := bankClient.AllBalances(
-				context.Background(),
-				&banktypes.QueryAllBalancesRequest{Address: address.String()},
-			)
+			balances, err := Paginate(func(key []byte) ([]sdk.Coin, []byte, error) {
+				bankRes, err := bankClient.AllBalances(
+					context.Background(),
+					&banktypes.QueryAllBalancesRequest{
+						Address: address.String(),
+						Pagination: &querytypes.PageRequest{
+							Key:   key,
+							Limit: config.Limit,
+						},
+					},
+				)
+				if err != nil {
+					return nil, nil, err
+				}
+				return bankRes.Balances, bankRes.Pagination.GetNextKey(), nil
+			})
 
 			if err != nil {
 				sublogger.Error().
@@ -120,7 +131,7 @@ func GetWalletMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *Wa
 				Float64("request-time", time.Since(queryStart).Seconds()).
 				Msg("Finished querying all balances")
 
-			for _, balance := range bankRes.Balances {
+			for _, balance := range balances {
 
 				// because cosmos dec doesn't have .toFloat64() method or whatever and returns everything as int
 				if value, err := strconv.ParseFloat(balance.Amount.String(), 64); err != nil {
@@ -184,10 +195,22 @@ func getWalletExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, met
 		queryStart := time.Now()
 
 		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
-		stakingRes, err := stakingClient.DelegatorDelegations(
-			context.Background(),
-			&stakingtypes.QueryDelegatorDelegationsRequest{DelegatorAddr: address.String()},
-		)
+		delegations, err := Paginate(func(key []byte) ([]stakingtypes.DelegationResponse, []byte, error) {
+			stakingRes, err := stakingClient.DelegatorDelegations(
+				context.Background(),
+				&stakingtypes.QueryDelegatorDelegationsRequest{
+					DelegatorAddr: address.String(),
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return stakingRes.DelegationResponses, stakingRes.Pagination.GetNextKey(), nil
+		})
 		if err != nil {
 			sublogger.Error().
 				Str("address", address.String()).
@@ -201,7 +224,7 @@ func getWalletExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, met
 			Float64("request-time", time.Since(queryStart).Seconds()).
 			Msg("Finished querying delegations")
 
-		for _, delegation := range stakingRes.DelegationResponses {
+		for _, delegation := range delegations {
 			// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
 			if value, err := strconv.ParseFloat(delegation.Balance.Amount.String(), 64); err != nil {
 				sublogger.Error().
@@ -227,10 +250,22 @@ func getWalletExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, met
 		queryStart := time.Now()
 
 		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
-		stakingRes, err := stakingClient.DelegatorUnbondingDelegations(
-			context.Background(),
-			&stakingtypes.QueryDelegatorUnbondingDelegationsRequest{DelegatorAddr: address.String()},
-		)
+		unbondings, err := Paginate(func(key []byte) ([]stakingtypes.UnbondingDelegation, []byte, error) {
+			stakingRes, err := stakingClient.DelegatorUnbondingDelegations(
+				context.Background(),
+				&stakingtypes.QueryDelegatorUnbondingDelegationsRequest{
+					DelegatorAddr: address.String(),
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return stakingRes.UnbondingResponses, stakingRes.Pagination.GetNextKey(), nil
+		})
 		if err != nil {
 			sublogger.Error().
 				Str("address", address.String()).
@@ -244,7 +279,7 @@ func getWalletExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, met
 			Float64("request-time", time.Since(queryStart).Seconds()).
 			Msg("Finished querying unbonding delegations")
 
-		for _, unbonding := range stakingRes.UnbondingResponses {
+		for _, unbonding := range unbondings {
 			var sum float64 = 0
 			for _, entry := range unbonding.Entries {
 				// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
@@ -275,10 +310,22 @@ func getWalletExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, met
 		queryStart := time.Now()
 
 		stakingClient := stakingtypes.NewQueryClient(s.GrpcConn)
-		stakingRes, err := stakingClient.Redelegations(
-			context.Background(),
-			&stakingtypes.QueryRedelegationsRequest{DelegatorAddr: address.String()},
-		)
+		redelegations, err := Paginate(func(key []byte) ([]stakingtypes.RedelegationResponse, []byte, error) {
+			stakingRes, err := stakingClient.Redelegations(
+				context.Background(),
+				&stakingtypes.QueryRedelegationsRequest{
+					DelegatorAddr: address.String(),
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return stakingRes.RedelegationResponses, stakingRes.Pagination.GetNextKey(), nil
+		})
 		if err != nil {
 			sublogger.Error().
 				Str("address", address.String()).
@@ -292,7 +339,7 @@ func getWalletExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, met
 			Float64("request-time", time.Since(queryStart).Seconds()).
 			Msg("Finished querying redelegations")
 
-		for _, redelegation := range stakingRes.RedelegationResponses {
+		for _, redelegation := range redelegations {
 			var sum float64 = 0
 			for _, entry := range redelegation.Entries {
 				// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
@@ -362,10 +409,11 @@ func getWalletExtendedMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, met
 
 }
 func (s *Service) WalletHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/wallet")
 	requestStart := time.Now()
 
 	sublogger := s.Log.With().
-		Str("request-id", uuid.New().String()).
+		Str("request-id", RequestID(r)).
 		Logger()
 
 	address := r.URL.Query().Get("address")
@@ -378,7 +426,7 @@ func (s *Service) WalletHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	registry := prometheus.NewRegistry()
+	registry := s.Config.NewRegistry()
 	walletMetrics := NewWalletMetrics(registry, s.Config)
 	walletExtendedMetrics := NewWalletExtendedMetrics(registry, s.Config)
 
@@ -387,8 +435,7 @@ func (s *Service) WalletHandler(w http.ResponseWriter, r *http.Request) {
 	getWalletExtendedMetrics(&wg, &sublogger, walletExtendedMetrics, s, s.Config, myAddress)
 	wg.Wait()
 
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-	h.ServeHTTP(w, r)
+	s.ServeMetrics(w, r, registry)
 	sublogger.Info().
 		Str("method", "GET").
 		Str("endpoint", "/metrics/wallet?address="+address).
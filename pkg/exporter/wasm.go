@@ -0,0 +1,262 @@
+package exporter
+
+import (
+	"context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/rs/zerolog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type WasmMetrics struct {
+	codeCountGauge     prometheus.Gauge
+	contractCountGauge prometheus.Gauge
+}
+
+type WasmContractMetrics struct {
+	balanceGauge *prometheus.GaugeVec
+}
+
+func NewWasmMetrics(reg prometheus.Registerer, config *ServiceConfig) *WasmMetrics {
+	m := &WasmMetrics{
+		codeCountGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_wasm_code_count",
+				Help:        "Number of CosmWasm codes stored on the chain",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+		contractCountGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_wasm_contract_count",
+				Help:        "Number of CosmWasm contracts instantiated on the chain, from all the codes",
+				ConstLabels: config.ConstLabels,
+			},
+		),
+	}
+	reg.MustRegister(m.codeCountGauge)
+	reg.MustRegister(m.contractCountGauge)
+	return m
+}
+func NewWasmContractMetrics(reg prometheus.Registerer, config *ServiceConfig) *WasmContractMetrics {
+	m := &WasmContractMetrics{
+		balanceGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "cosmos_wasm_contract_balance",
+				Help:        "Balance of the CosmWasm contract",
+				ConstLabels: config.ConstLabels,
+			},
+			[]string{"address", "denom"},
+		),
+	}
+	reg.MustRegister(m.balanceGauge)
+	return m
+}
+func GetWasmMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *WasmMetrics, s *Service, config *ServiceConfig) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().Msg("Started querying wasm codes")
+		queryStart := time.Now()
+
+		// QueryCodesRequest { PageRequest pagination = 1; }, QueryCodesResponse { repeated CodeInfoResponse code_infos = 1; ... }
+		codeInfos, err := s.rawPaginatedQuery("/cosmwasm.wasm.v1.Query/Codes", nil, 1, 1, config.Limit)
+		if err != nil {
+			sublogger.Error().Err(err).Msg("Could not get wasm codes")
+			return
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int("codesLength", len(codeInfos)).
+			Msg("Finished querying wasm codes")
+		metrics.codeCountGauge.Set(float64(len(codeInfos)))
+
+		contracts := 0
+		for _, codeInfo := range codeInfos {
+			// CodeInfoResponse { uint64 code_id = 1; ... }
+			codeID, err := rawVarintField(codeInfo, 1)
+			if err != nil {
+				sublogger.Error().Err(err).Msg("Could not decode wasm code info")
+				return
+			}
+
+			// QueryContractsByCodeRequest { uint64 code_id = 1; PageRequest pagination = 2; },
+			// QueryContractsByCodeResponse { repeated string contracts = 1; ... }
+			request := protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), codeID)
+			codeContracts, err := s.rawPaginatedQuery("/cosmwasm.wasm.v1.Query/ContractsByCode", request, 2, 1, config.Limit)
+			if err != nil {
+				sublogger.Error().
+					Uint64("code-id", codeID).
+					Err(err).
+					Msg("Could not get wasm contracts by code")
+				return
+			}
+			contracts += len(codeContracts)
+		}
+
+		sublogger.Debug().
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Int("contractsLength", contracts).
+			Msg("Finished querying wasm contracts")
+		metrics.contractCountGauge.Set(float64(contracts))
+	}()
+
+}
+
+// rawPaginatedQuery pages through a query of a module we don't have the go types for, returning the values of the repeated field of every page.
+// request is the encoded request without its pagination field, and the responses are expected to have their
+// PageResponse pagination as the field following the repeated one, as the SDK queries do.
+func (s *Service) rawPaginatedQuery(method string, request []byte, paginationField protowire.Number, field protowire.Number, limit uint64) ([][]byte, error) {
+	return Paginate(func(key []byte) ([][]byte, []byte, error) {
+		pagination, err := (&querytypes.PageRequest{Key: key, Limit: limit}).Marshal()
+		if err != nil {
+			return nil, nil, err
+		}
+		pageRequest := protowire.AppendBytes(protowire.AppendTag(append([]byte(nil), request...), paginationField, protowire.BytesType), pagination)
+
+		response, err := s.rawQuery(context.Background(), method, pageRequest)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		values, err := rawBytesFields(response, field)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pageResponse, err := rawBytesField(response, field+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		// PageResponse { bytes next_key = 1; uint64 total = 2; }
+		nextKey, err := rawBytesField(pageResponse, 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return values, nextKey, nil
+	})
+}
+func GetWasmContractMetrics(wg *sync.WaitGroup, sublogger *zerolog.Logger, metrics *WasmContractMetrics, s *Service, config *ServiceConfig, address sdk.AccAddress) {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sublogger.Debug().
+			Str("address", address.String()).
+			Msg("Started querying contract balances")
+		queryStart := time.Now()
+
+		bankClient := banktypes.NewQueryClient(s.GrpcConn)
+
+		// contracts like DEXes can hold a lot of denoms
+		balances, err := Paginate(func(key []byte) ([]sdk.Coin, []byte, error) {
+			bankRes, err := bankClient.AllBalances(
+				context.Background(),
+				&banktypes.QueryAllBalancesRequest{
+					Address: address.String(),
+					Pagination: &querytypes.PageRequest{
+						Key:   key,
+						Limit: config.Limit,
+					},
+				},
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			return bankRes.Balances, bankRes.Pagination.GetNextKey(), nil
+		})
+		if err != nil {
+			sublogger.Error().
+				Str("address", address.String()).
+				Err(err).
+				Msg("Could not get contract balances")
+			return
+		}
+
+		sublogger.Debug().
+			Str("address", address.String()).
+			Float64("request-time", time.Since(queryStart).Seconds()).
+			Msg("Finished querying contract balances")
+
+		for _, balance := range balances {
+			// because cosmos's dec doesn't have .toFloat64() method or whatever and returns everything as int
+			if value, err := strconv.ParseFloat(balance.Amount.String(), 64); err != nil {
+				sublogger.Error().
+					Str("address", address.String()).
+					Err(err).
+					Msg("Could not parse contract balance")
+			} else {
+				metrics.balanceGauge.With(prometheus.Labels{
+					"address": address.String(),
+					"denom":   balance.Denom,
+				}).Set(value / config.DenomCoefficient)
+			}
+		}
+	}()
+
+}
+func (s *Service) WasmHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/wasm")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	registry := s.Config.NewRegistry()
+	wasmMetrics := NewWasmMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetWasmMetrics(&wg, &sublogger, wasmMetrics, s, s.Config)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/wasm").
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}
+func (s *Service) WasmContractHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.ForEndpoint(r, "/metrics/wasm/contract")
+	requestStart := time.Now()
+
+	sublogger := s.Log.With().
+		Str("request-id", RequestID(r)).
+		Logger()
+
+	address := r.URL.Query().Get("contract_address")
+	contractAddress, err := sdk.AccAddressFromBech32(address)
+	if err != nil {
+		sublogger.Error().
+			Str("address", address).
+			Err(err).
+			Msg("Could not get contract address")
+		return
+	}
+
+	registry := s.Config.NewRegistry()
+	wasmContractMetrics := NewWasmContractMetrics(registry, s.Config)
+
+	var wg sync.WaitGroup
+	GetWasmContractMetrics(&wg, &sublogger, wasmContractMetrics, s, s.Config, contractAddress)
+
+	wg.Wait()
+
+	s.ServeMetrics(w, r, registry)
+	sublogger.Info().
+		Str("method", "GET").
+		Str("endpoint", "/metrics/wasm/contract?contract_address="+address).
+		Float64("request-time", time.Since(requestStart).Seconds()).
+		Msg("Request processed")
+}